@@ -0,0 +1,131 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	firebase "firebase.google.com/go"
+	"firebase.google.com/go/auth"
+	"github.com/hashicorp/terraform/helper/schema"
+	"google.golang.org/api/option"
+)
+
+const identityToolkitV1Endpoint = "https://identitytoolkit.googleapis.com/v1"
+
+// accountsMethods maps the relyingparty methods used by the Admin SDK to
+// their project and tenant scoped Identity Platform counterparts, which
+// accept the same request and response bodies.
+var accountsMethods = map[string]string{
+	"deleteAccount":          "accounts:delete",
+	"getAccountInfo":         "accounts:lookup",
+	"getOobConfirmationCode": "accounts:sendOobCode",
+	"setAccountInfo":         "accounts:update",
+	"signupNewUser":          "accounts",
+	"uploadAccount":          "accounts:batchCreate",
+}
+
+// authClient returns the auth client for the resource's project and, if
+// the resource has one, its tenant.
+func authClient(d *schema.ResourceData, meta interface{}) (*auth.Client, error) {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	var tenantID string
+	if v, ok := d.GetOk("tenant_id"); ok {
+		tenantID = v.(string)
+	}
+	return client.tenantAuth(tenantID)
+}
+
+// tenantAuth returns an auth client whose user management calls are scoped
+// to the given Identity Platform tenant, or the project's auth client if
+// tenantID is empty.
+func (c Client) tenantAuth(tenantID string) (*auth.Client, error) {
+	if tenantID == "" {
+		return c.Auth, nil
+	}
+
+	c.tenants.Lock()
+	defer c.tenants.Unlock()
+
+	key := c.ProjectID + "/" + tenantID
+	if client, ok := c.tenants.clients[key]; ok {
+		return client, nil
+	}
+
+	log.Printf("[DEBUG] Getting auth client for tenant: %s", tenantID)
+
+	hc := &http.Client{
+		Transport: &accountsTransport{
+			projectID: c.ProjectID,
+			tenantID:  tenantID,
+			base:      c.HTTP.Transport,
+		},
+	}
+
+	ctx := context.Background()
+	config := &firebase.Config{ProjectID: c.ProjectID}
+	app, err := firebase.NewApp(ctx, config, c.credentials, option.WithHTTPClient(hc))
+	if err != nil {
+		return nil, err
+	}
+	client, err := app.Auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.tenants.clients[key] = client
+	return client, nil
+}
+
+// accountsTransport redirects relyingparty requests to the accounts
+// endpoints of a project or one of its tenants. Other requests are passed
+// on unchanged, except for relyingparty methods without an accounts
+// counterpart, which would reach the project's users instead of the
+// tenant's. The base transport is expected to authorize requests.
+type accountsTransport struct {
+	projectID string
+	tenantID  string
+	base      http.RoundTripper
+}
+
+func (t *accountsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	prefix := identityToolkitEndpoint + "/"
+	u := *req.URL
+	u.RawQuery = ""
+	if !strings.HasPrefix(u.String(), prefix) {
+		return t.base.RoundTrip(req)
+	}
+
+	method := strings.TrimPrefix(u.String(), prefix)
+	v1, ok := accountsMethods[method]
+	if !ok {
+		if t.tenantID != "" {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("%s is not supported for tenant %s", method, t.tenantID)
+		}
+		return t.base.RoundTrip(req)
+	}
+
+	parent := fmt.Sprintf("%s/projects/%s", identityToolkitV1Endpoint, t.projectID)
+	if t.tenantID != "" {
+		parent += "/tenants/" + t.tenantID
+	}
+	to, err := url.Parse(parent + "/" + v1)
+	if err != nil {
+		return nil, err
+	}
+	to.RawQuery = req.URL.RawQuery
+
+	r := new(http.Request)
+	*r = *req
+	r.URL = to
+	r.Host = to.Host
+	return t.base.RoundTrip(r)
+}
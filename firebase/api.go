@@ -0,0 +1,80 @@
+package firebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// sendRequest issues a JSON request against a Google REST API that is not
+// covered by the Admin SDK and decodes the response into result.
+func sendRequest(ctx context.Context, client *http.Client, method, url string, body, result interface{}) error {
+	_, err := sendRequestWithHeader(ctx, client, method, url, nil, body, result)
+	return err
+}
+
+// sendRequestWithHeader is sendRequest with additional request headers, such
+// as If-Match. It returns the response headers.
+func sendRequestWithHeader(ctx context.Context, client *http.Client, method, url string, header http.Header, body, result interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Printf("[DEBUG] %s %s", method, url)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if err := googleapi.CheckResponse(res); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return res.Header, nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return res.Header, nil
+}
+
+// isNotFound reports whether err is a 404 returned by sendRequest.
+func isNotFound(err error) bool {
+	if e, ok := err.(*googleapi.Error); ok {
+		return e.Code == http.StatusNotFound
+	}
+	return false
+}
+
+// isPreconditionFailed reports whether err is a 412 returned by
+// sendRequestWithHeader, when an If-Match ETag is outdated.
+func isPreconditionFailed(err error) bool {
+	if e, ok := err.(*googleapi.Error); ok {
+		return e.Code == http.StatusPreconditionFailed
+	}
+	return false
+}
@@ -2,28 +2,83 @@ package firebase
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 
 	firebase "firebase.google.com/go"
 
 	"firebase.google.com/go/auth"
 	"firebase.google.com/go/db"
+	"firebase.google.com/go/iid"
 	"firebase.google.com/go/messaging"
 	"firebase.google.com/go/storage"
 
 	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
 )
 
+// firebaseScopes are the OAuth2 scopes requested by the Admin SDK, reused
+// for REST calls that are not covered by the Admin SDK clients.
+var firebaseScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/datastore",
+	"https://www.googleapis.com/auth/devstorage.full_control",
+	"https://www.googleapis.com/auth/firebase",
+	"https://www.googleapis.com/auth/identitytoolkit",
+	"https://www.googleapis.com/auth/userinfo.email",
+}
+
 type Config struct {
 	ServiceAccountKey string
+
+	// Project is the default project, the service account's own project
+	// if empty.
+	Project string
+
+	// Endpoints redirects requests whose URL starts with a key to the
+	// corresponding base URL, e.g. to serve public keys from a test server.
+	Endpoints map[string]string
 }
 
 type Client struct {
-	App       firebase.App
-	Auth      *auth.Client
-	DB        *db.Client
-	Messaging *messaging.Client
-	Storage   *storage.Client
+	App        *firebase.App
+	Auth       *auth.Client
+	DB         *db.Client
+	InstanceID *iid.Client
+	Messaging  *messaging.Client
+	Storage    *storage.Client
+	HTTP       *http.Client
+	ProjectID  string
+
+	// credentials authenticate additional apps, e.g. for tenants
+	credentials option.ClientOption
+	// credentialsJSON is the service account key, if any, which signs
+	// tokens the Admin SDK cannot mint
+	credentialsJSON []byte
+	// credentialsProject is the project of the service account
+	credentialsProject string
+	// projects caches the clients of other projects
+	projects *projectClients
+	// tenants caches the auth clients of tenants
+	tenants *tenantClients
+	// userClaims detects users of a plan sharing an email or phone number
+	userClaims *userClaims
+}
+
+// projectClients caches a Client per project so apps are built only once.
+type projectClients struct {
+	sync.Mutex
+	clients map[string]Client
+}
+
+// tenantClients caches an auth client per project and tenant.
+type tenantClients struct {
+	sync.Mutex
+	clients map[string]*auth.Client
 }
 
 // Client configures and returns a fully initialized firebase app client
@@ -36,19 +91,105 @@ func (c Config) Client() (interface{}, error) {
 
 	opt := option.WithCredentialsFile(c.ServiceAccountKey)
 
-	log.Println("[INFO] Create new firebase app client")
+	log.Println("[INFO] Getting HTTP client")
 
-	app, err := firebase.NewApp(ctx, nil, opt)
+	// Get an authenticated HTTP client for the REST APIs
+	client.HTTP, _, err = transport.NewHTTPClient(ctx, option.WithScopes(firebaseScopes...), opt)
 	if err != nil {
 		return nil, err
 	}
 
+	// The project ID is needed to address project scoped REST resources
+	creds, err := transport.Creds(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	client.credentials = option.WithCredentials(creds)
+	client.credentialsJSON = creds.JSON
+	client.credentialsProject = creds.ProjectID
+	client.projects = &projectClients{clients: make(map[string]Client)}
+	client.tenants = &tenantClients{clients: make(map[string]*auth.Client)}
+	client.userClaims = &userClaims{uids: make(map[string]string)}
+
+	if len(c.Endpoints) > 0 {
+		log.Printf("[INFO] Using endpoint overrides %v", c.Endpoints)
+		client.HTTP.Transport = &endpointTransport{
+			endpoints: c.Endpoints,
+			base:      client.HTTP.Transport,
+		}
+	}
+
+	project := c.Project
+	if project == "" {
+		project = creds.ProjectID
+	}
+	return client.newApp(ctx, project, len(c.Endpoints) > 0)
+}
+
+// forProject returns the client of the given project, or c if project is
+// empty. Clients of other projects share c's credentials, so the service
+// account needs access to those projects.
+func (c Client) forProject(project string) (Client, error) {
+	if project == "" || project == c.ProjectID {
+		return c, nil
+	}
+
+	c.projects.Lock()
+	defer c.projects.Unlock()
+
+	if client, ok := c.projects.clients[project]; ok {
+		return client, nil
+	}
+
+	client, err := c.newApp(context.Background(), project, true)
+	if err != nil {
+		return Client{}, fmt.Errorf("Error configuring project (%s): %s", project, err)
+	}
+	c.projects.clients[project] = client
+	return client, nil
+}
+
+// newApp returns a copy of c whose firebase.App and service clients manage
+// project. The app shares c's HTTP client if shareHTTP is set.
+func (c Client) newApp(ctx context.Context, project string, shareHTTP bool) (Client, error) {
+	var err error
+	client := c
+	client.ProjectID = project
+
+	opts := []option.ClientOption{c.credentials}
+	var config *firebase.Config
+	if project != c.credentialsProject {
+		log.Printf("[INFO] Using project %s", project)
+
+		// Account management is scoped to the service account's project
+		// unless it is addressed explicitly
+		client.HTTP = &http.Client{
+			Transport: &accountsTransport{
+				projectID: project,
+				base:      c.HTTP.Transport,
+			},
+		}
+		config = &firebase.Config{ProjectID: project}
+		shareHTTP = true
+	}
+	if shareHTTP {
+		opts = append(opts, option.WithHTTPClient(client.HTTP))
+	}
+
+	log.Println("[INFO] Create new firebase app client")
+
+	client.App, err = firebase.NewApp(ctx, config, opts...)
+	if err != nil {
+		return client, err
+	}
+	app := client.App
+
 	log.Println("[INFO] Getting auth client")
 
 	// Get an auth client from the firebase.App
 	client.Auth, err = app.Auth(ctx)
 	if err != nil {
-		return nil, err
+		return client, err
 	}
 
 	// log.Println("[INFO] Getting database client")
@@ -56,15 +197,23 @@ func (c Config) Client() (interface{}, error) {
 	// // Get a database client from the firebase.App
 	// client.DB, err = app.Database(ctx)
 	// if err != nil {
-	// 	return nil, err
+	// 	return client, err
 	// }
 
+	log.Println("[INFO] Getting instance ID client")
+
+	// Get an instance ID client from the firebase.App
+	client.InstanceID, err = app.InstanceID(ctx)
+	if err != nil {
+		return client, err
+	}
+
 	log.Println("[INFO] Getting messaging client")
 
 	// Get a messaging client from the firebase.App
 	client.Messaging, err = app.Messaging(ctx)
 	if err != nil {
-		return nil, err
+		return client, err
 	}
 
 	log.Println("[INFO] Getting storage client")
@@ -72,8 +221,38 @@ func (c Config) Client() (interface{}, error) {
 	// Get a storage client from the firebase.App
 	client.Storage, err = app.Storage(ctx)
 	if err != nil {
-		return nil, err
+		return client, err
 	}
 
 	return client, nil
 }
+
+// endpointTransport rewrites requests for overridden endpoints before handing
+// them to the base transport.
+type endpointTransport struct {
+	endpoints map[string]string
+	base      http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Prefer the longest matching prefix so overrides can be nested
+	var from string
+	for prefix := range t.endpoints {
+		if strings.HasPrefix(req.URL.String(), prefix) && len(prefix) > len(from) {
+			from = prefix
+		}
+	}
+	if from == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	u, err := url.Parse(t.endpoints[from] + strings.TrimPrefix(req.URL.String(), from))
+	if err != nil {
+		return nil, err
+	}
+	r := new(http.Request)
+	*r = *req
+	r.URL = u
+	r.Host = u.Host
+	return t.base.RoundTrip(r)
+}
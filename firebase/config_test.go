@@ -0,0 +1,71 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestClientForProject(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	lookup := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"users":[{"localId":%q,"validSince":"1500000000"}]}`, testUser.UserInfo.UID)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/relyingparty/getAccountInfo", lookup)
+	mux.HandleFunc("/v1/projects/staging/accounts:lookup", lookup)
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		"https://identitytoolkit.googleapis.com": srv.URL,
+		identityToolkitEndpoint:                  srv.URL + "/relyingparty",
+	})
+	if meta.ProjectID != testProjectID {
+		t.Fatalf("incorrect default project: %q", meta.ProjectID)
+	}
+
+	if c, err := meta.forProject(""); err != nil || c.Auth != meta.Auth {
+		t.Fatalf("expected the default client, got error: %v", err)
+	}
+	staging, err := meta.forProject("staging")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if staging.ProjectID != "staging" || staging.Auth == meta.Auth {
+		t.Fatalf("expected a client for staging, got %q", staging.ProjectID)
+	}
+	if again, _ := meta.forProject("staging"); again.Auth != staging.Auth {
+		t.Fatalf("staging client was not cached")
+	}
+
+	if _, err := meta.Auth.GetUser(context.Background(), testUser.UserInfo.UID); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	r := resourceFirebaseUserSessionRevocation()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"uid":     testUser.UserInfo.UID,
+		"project": "staging",
+	})
+	d.SetId("revocation")
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("tokens_valid_after_millis") != 1500000000000 {
+		t.Fatalf("incorrect tokens_valid_after_millis: %v", d.Get("tokens_valid_after_millis"))
+	}
+
+	expected := "[/relyingparty/getAccountInfo /v1/projects/staging/accounts:lookup]"
+	if fmt.Sprint(paths) != expected {
+		t.Fatalf("expected requests %s, got %v", expected, paths)
+	}
+}
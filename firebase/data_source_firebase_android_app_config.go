@@ -0,0 +1,12 @@
+package firebase
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceFirebaseAndroidAppConfig() *schema.Resource {
+	return &schema.Resource{
+		Read:   readMobileAppConfig(firebaseAndroidAppType),
+		Schema: mobileAppConfigSchema(),
+	}
+}
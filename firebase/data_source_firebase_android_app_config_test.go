@@ -0,0 +1,40 @@
+package firebase
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceFirebaseMobileAppConfig(t *testing.T) {
+	fake := newTestAppsServer()
+	meta, closeServer := testAPIClient(t, fake, firebaseManagementEndpoint)
+	defer closeServer()
+
+	cases := []struct {
+		Resource *schema.Resource
+		AppID    string
+		Filename string
+	}{
+		{dataSourceFirebaseAndroidAppConfig(), "1:123456789012:android:0", "google-services.json"},
+		{dataSourceFirebaseAppleAppConfig(), "1:123456789012:ios:0", "GoogleService-Info.plist"},
+	}
+	for _, tc := range cases {
+		fake.apps[tc.AppID] = map[string]interface{}{"state": "ACTIVE"}
+
+		d := schema.TestResourceDataRaw(t, tc.Resource.Schema, map[string]interface{}{
+			"app_id": tc.AppID,
+		})
+		if err := tc.Resource.Read(d, meta); err != nil {
+			t.Fatalf("%s: err: %s", tc.AppID, err)
+		}
+		if d.Get("config_filename") != tc.Filename {
+			t.Fatalf("%s: incorrect config_filename: %s", tc.AppID, d.Get("config_filename"))
+		}
+		contents, err := base64.StdEncoding.DecodeString(d.Get("config_file_contents").(string))
+		if err != nil || string(contents) != tc.AppID {
+			t.Fatalf("%s: incorrect config_file_contents: %q %v", tc.AppID, contents, err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package firebase
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceFirebaseAppleAppConfig() *schema.Resource {
+	return &schema.Resource{
+		Read:   readMobileAppConfig(firebaseAppleAppType),
+		Schema: mobileAppConfigSchema(),
+	}
+}
@@ -0,0 +1,116 @@
+package firebase
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceFirebaseCustomToken(t *testing.T) {
+	key, pk := testServiceAccountKey(t, "https://oauth2.googleapis.com/token")
+	defer os.RemoveAll(filepath.Dir(key))
+
+	meta, err := Config{ServiceAccountKey: key}.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceFirebaseCustomToken().Schema, map[string]interface{}{
+		"uid":    testUser.UserInfo.UID,
+		"claims": `{"admin":true,"package":"gold"}`,
+	})
+	if err := dataSourceFirebaseCustomTokenRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if d.Id() != testUser.UserInfo.UID {
+		t.Fatalf("incorrect ID: %q", d.Id())
+	}
+
+	segments := strings.Split(d.Get("token").(string), ".")
+	if len(segments) != 3 {
+		t.Fatalf("incorrect number of token segments: %d", len(segments))
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	hash := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	if err := rsa.VerifyPKCS1v15(&pk.PublicKey, crypto.SHA256, hash[:], sig); err != nil {
+		t.Fatalf("token signature does not verify: %s", err)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var payload struct {
+		UID    string                 `json:"uid"`
+		Claims map[string]interface{} `json:"claims"`
+	}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if payload.UID != testUser.UserInfo.UID {
+		t.Fatalf("incorrect uid claim: %q", payload.UID)
+	}
+	if payload.Claims["admin"] != true || payload.Claims["package"] != "gold" {
+		t.Fatalf("incorrect developer claims: %#v", payload.Claims)
+	}
+}
+
+func TestDataSourceFirebaseCustomToken_tenant(t *testing.T) {
+	key, pk := testServiceAccountKey(t, "https://oauth2.googleapis.com/token")
+	defer os.RemoveAll(filepath.Dir(key))
+
+	meta, err := Config{ServiceAccountKey: key}.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceFirebaseCustomToken().Schema, map[string]interface{}{
+		"uid":       testUser.UserInfo.UID,
+		"tenant_id": "acme-0",
+		"claims":    `{"admin":true}`,
+	})
+	if err := dataSourceFirebaseCustomTokenRead(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	segments := strings.Split(d.Get("token").(string), ".")
+	if len(segments) != 3 {
+		t.Fatalf("incorrect number of token segments: %d", len(segments))
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(segments[2])
+	hash := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	if err := rsa.VerifyPKCS1v15(&pk.PublicKey, crypto.SHA256, hash[:], sig); err != nil {
+		t.Fatalf("token signature does not verify: %s", err)
+	}
+
+	b, _ := base64.RawURLEncoding.DecodeString(segments[1])
+	var payload struct {
+		Iss      string                 `json:"iss"`
+		Aud      string                 `json:"aud"`
+		UID      string                 `json:"uid"`
+		TenantID string                 `json:"tenant_id"`
+		Claims   map[string]interface{} `json:"claims"`
+	}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if payload.TenantID != "acme-0" || payload.UID != testUser.UserInfo.UID || payload.Claims["admin"] != true {
+		t.Fatalf("incorrect payload: %#v", payload)
+	}
+	if payload.Iss != "mock-email@mock-project.iam.gserviceaccount.com" || payload.Aud != customTokenAudience {
+		t.Fatalf("incorrect issuer or audience: %s %s", payload.Iss, payload.Aud)
+	}
+}
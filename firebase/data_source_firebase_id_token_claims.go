@@ -0,0 +1,137 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"firebase.google.com/go/auth"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/structure"
+)
+
+// idTokenStandardClaims are the claims Firebase Auth sets on every ID token
+// in addition to the ones decoded into auth.Token.
+var idTokenStandardClaims = []string{
+	"auth_time", "email", "email_verified", "firebase", "name",
+	"phone_number", "picture", "user_id",
+}
+
+func dataSourceFirebaseIDTokenClaims() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceFirebaseIDTokenClaimsRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"id_token": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			// Only accepts tokens of users signed in to the tenant, whose
+			// revocation is checked against the tenant's users
+			"tenant_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTenantID,
+			},
+			"check_revoked": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"uid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"audience": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"auth_time": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"issued_at": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"expires": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"custom_claims": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceFirebaseIDTokenClaimsRead(d *schema.ResourceData, meta interface{}) error {
+	log.Println("[INFO] Verifying ID token")
+
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
+	idToken := d.Get("id_token").(string)
+
+	var token *auth.Token
+	if d.Get("check_revoked").(bool) {
+		token, err = client.VerifyIDTokenAndCheckRevoked(context.Background(), idToken)
+	} else {
+		token, err = client.VerifyIDToken(context.Background(), idToken)
+	}
+	if err != nil {
+		if auth.IsIDTokenRevoked(err) {
+			return fmt.Errorf("ID token has been revoked")
+		}
+		return fmt.Errorf("Error verifying ID token: %s", err)
+	}
+
+	if tenantID := d.Get("tenant_id").(string); tenantID != "" {
+		var tenant string
+		if firebase, ok := token.Claims["firebase"].(map[string]interface{}); ok {
+			tenant, _ = firebase["tenant"].(string)
+		}
+		if tenant != tenantID {
+			return fmt.Errorf("ID token was not issued for tenant %s", tenantID)
+		}
+	}
+
+	claims := make(map[string]interface{})
+	for k, v := range token.Claims {
+		claims[k] = v
+	}
+	for _, k := range idTokenStandardClaims {
+		delete(claims, k)
+	}
+	customClaims := ""
+	if len(claims) > 0 {
+		customClaims, err = structure.FlattenJsonToString(claims)
+		if err != nil {
+			return err
+		}
+	}
+
+	var authTime int64
+	if v, ok := token.Claims["auth_time"].(float64); ok {
+		authTime = int64(v)
+	}
+
+	d.SetId(token.UID)
+	d.Set("uid", token.UID)
+	d.Set("issuer", token.Issuer)
+	d.Set("audience", token.Audience)
+	d.Set("auth_time", authTime)
+	d.Set("issued_at", token.IssuedAt)
+	d.Set("expires", token.Expires)
+	d.Set("custom_claims", customClaims)
+
+	return nil
+}
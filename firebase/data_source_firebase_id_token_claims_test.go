@@ -0,0 +1,160 @@
+package firebase
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const (
+	testIDTokenCertURL = "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
+	testIDTokenKeyID   = "mock-signing-key"
+)
+
+// testIDTokenServer serves the ID token signing certificates and
+// getAccountInfo for a single user whose tokens are valid after validSince
+// (seconds).
+func testIDTokenServer(t *testing.T, pk *rsa.PrivateKey, validSince *int64) *httptest.Server {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "securetoken.system.gserviceaccount.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &pk.PublicKey, pk)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	certs := map[string]string{
+		testIDTokenKeyID: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		json.NewEncoder(w).Encode(certs)
+	})
+	mux.HandleFunc("/getAccountInfo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"users":[{"localId":%q,"validSince":"%d"}]}`, testUser.UserInfo.UID, *validSince)
+	})
+	return testServer(mux)
+}
+
+func testSignIDToken(t *testing.T, pk *rsa.PrivateKey, payload map[string]interface{}) string {
+	encode := func(i interface{}) string {
+		b, err := json.Marshal(i)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	ss := encode(map[string]string{"alg": "RS256", "typ": "JWT", "kid": testIDTokenKeyID}) + "." + encode(payload)
+	hash := sha256.Sum256([]byte(ss))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, pk, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return ss + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestDataSourceFirebaseIDTokenClaims(t *testing.T) {
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	now := time.Now().Unix()
+	validSince := now - 3600
+	srv := testIDTokenServer(t, pk, &validSince)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		testIDTokenCertURL:      srv.URL + "/certs",
+		identityToolkitEndpoint: srv.URL,
+	})
+
+	payload := func(aud string) map[string]interface{} {
+		return map[string]interface{}{
+			"iss":       "https://securetoken.google.com/" + testProjectID,
+			"aud":       aud,
+			"iat":       now - 60,
+			"exp":       now + 3600,
+			"auth_time": now - 120,
+			"sub":       testUser.UserInfo.UID,
+			"user_id":   testUser.UserInfo.UID,
+			"email":     testUser.UserInfo.Email,
+			"firebase":  map[string]interface{}{"sign_in_provider": "password"},
+			"admin":     true,
+			"package":   "gold",
+		}
+	}
+	validToken := testSignIDToken(t, pk, payload(testProjectID))
+
+	read := func(raw map[string]interface{}) (*schema.ResourceData, error) {
+		d := schema.TestResourceDataRaw(t, dataSourceFirebaseIDTokenClaims().Schema, raw)
+		return d, dataSourceFirebaseIDTokenClaimsRead(d, meta)
+	}
+
+	d, err := read(map[string]interface{}{"id_token": validToken})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"uid":           testUser.UserInfo.UID,
+		"issuer":        "https://securetoken.google.com/" + testProjectID,
+		"audience":      testProjectID,
+		"auth_time":     int(now - 120),
+		"issued_at":     int(now - 60),
+		"expires":       int(now + 3600),
+		"custom_claims": `{"admin":true,"package":"gold"}`,
+	}
+	for k, v := range expected {
+		if d.Get(k) != v {
+			t.Fatalf("incorrect %s: expected %#v, got %#v", k, v, d.Get(k))
+		}
+	}
+
+	_, err = read(map[string]interface{}{"id_token": validToken, "check_revoked": true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	validSince = now
+	_, err = read(map[string]interface{}{"id_token": validToken, "check_revoked": true})
+	if err == nil || !strings.Contains(err.Error(), "revoked") {
+		t.Fatalf("expected revoked error, got: %v", err)
+	}
+
+	_, err = read(map[string]interface{}{"id_token": testSignIDToken(t, pk, payload("other-project"))})
+	if err == nil || !strings.Contains(err.Error(), "audience") {
+		t.Fatalf("expected audience error, got: %v", err)
+	}
+
+	tenantPayload := payload(testProjectID)
+	tenantPayload["firebase"] = map[string]interface{}{"sign_in_provider": "password", "tenant": "acme-0"}
+	tenantToken := testSignIDToken(t, pk, tenantPayload)
+	if _, err := read(map[string]interface{}{"id_token": tenantToken, "tenant_id": "acme-0"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for token, tenantID := range map[string]string{tenantToken: "other-0", validToken: "acme-0"} {
+		_, err = read(map[string]interface{}{"id_token": token, "tenant_id": tenantID})
+		if err == nil || !strings.Contains(err.Error(), "not issued for tenant "+tenantID) {
+			t.Fatalf("expected tenant error, got: %v", err)
+		}
+	}
+}
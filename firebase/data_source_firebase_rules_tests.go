@@ -0,0 +1,258 @@
+package firebase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type rulesTestCase struct {
+	Expectation string                 `json:"expectation"`
+	Request     map[string]interface{} `json:"request"`
+	Resource    map[string]interface{} `json:"resource,omitempty"`
+}
+
+type rulesTestResult struct {
+	State         string   `json:"state"`
+	DebugMessages []string `json:"debugMessages"`
+	ErrorPosition *struct {
+		FileName string `json:"fileName"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"errorPosition"`
+}
+
+func dataSourceFirebaseRulesTest() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceFirebaseRulesTestRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"file": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"content": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"test_case": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"get", "list", "create", "update", "delete",
+							}, false),
+						},
+						// Unauthenticated without a uid
+						"auth_uid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"auth_claims": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.ValidateJsonString,
+						},
+						// Data of the existing resource
+						"resource_data": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.ValidateJsonString,
+						},
+						// Data written by the request
+						"request_data": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.ValidateJsonString,
+						},
+						"expectation": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"ALLOW", "DENY",
+							}, false),
+						},
+					},
+				},
+			},
+			"passed": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceFirebaseRulesTestRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	source := expandRulesSource(d.Get("file"))
+	cases := d.Get("test_case").([]interface{})
+	testCases := make([]*rulesTestCase, 0, len(cases))
+	for _, v := range cases {
+		testCase, err := expandRulesTestCase(v.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		testCases = append(testCases, testCase)
+	}
+	body := map[string]interface{}{
+		"source": source,
+		"testSuite": map[string]interface{}{
+			"testCases": testCases,
+		},
+	}
+
+	httpClient, url := client.HTTP, rulesProjectURL(client.ProjectID)+":test"
+	header := http.Header{}
+	if host := rulesEmulatorHost(source); host != "" {
+		log.Printf("[INFO] Using rules emulator: %s", host)
+		httpClient, url = http.DefaultClient, fmt.Sprintf("http://%s/v1/projects/%s:test", host, client.ProjectID)
+		header.Set("Authorization", "Bearer owner")
+	}
+
+	log.Printf("[INFO] Running %d rules test cases", len(testCases))
+
+	var result struct {
+		Issues      []rulesIssue      `json:"issues"`
+		TestResults []rulesTestResult `json:"testResults"`
+	}
+	_, err = sendRequestWithHeader(context.Background(), httpClient, "POST", url, header, body, &result)
+	if err != nil {
+		return fmt.Errorf("Error testing rules: %s", err)
+	}
+	if err := rulesIssuesError(result.Issues); err != nil {
+		return err
+	}
+	if len(result.TestResults) != len(testCases) {
+		return fmt.Errorf("Error testing rules: got %d results for %d test cases", len(result.TestResults), len(testCases))
+	}
+
+	// Each failed case is a row with why it failed
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tMETHOD\tPATH\tEXPECTED\tREASON")
+	passed := 0
+	for i, r := range result.TestResults {
+		if r.State == "SUCCESS" {
+			passed++
+			continue
+		}
+		c := cases[i].(map[string]interface{})
+		name := c["name"].(string)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		reason := strings.Join(r.DebugMessages, "; ")
+		if p := r.ErrorPosition; p != nil && p.Line > 0 {
+			reason = strings.TrimSuffix(fmt.Sprintf("%s:%d:%d: %s", p.FileName, p.Line, p.Column, reason), ": ")
+		}
+		if reason == "" {
+			reason = "expectation not met"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, c["method"], c["path"], c["expectation"], reason)
+	}
+	w.Flush()
+
+	if passed < len(testCases) {
+		return fmt.Errorf("%d of %d rules test cases failed:\n\n%s", len(testCases)-passed, len(testCases), table.String())
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(b)
+	d.SetId(hex.EncodeToString(hash[:]))
+	d.Set("passed", passed)
+
+	return nil
+}
+
+// rulesEmulatorHost returns the host of the emulator of the rules' service,
+// or an empty string when it is not set.
+func rulesEmulatorHost(source *rulesSource) string {
+	if rulesRulesetService(&rulesRuleset{Source: source}) == "firebase.storage" {
+		return os.Getenv("FIREBASE_STORAGE_EMULATOR_HOST")
+	}
+	return os.Getenv("FIRESTORE_EMULATOR_HOST")
+}
+
+func expandRulesTestCase(c map[string]interface{}) (*rulesTestCase, error) {
+	request := map[string]interface{}{
+		"path":   c["path"].(string),
+		"method": c["method"].(string),
+	}
+
+	if uid := c["auth_uid"].(string); uid != "" {
+		token := map[string]interface{}{}
+		if v := c["auth_claims"].(string); v != "" {
+			if err := json.Unmarshal([]byte(v), &token); err != nil {
+				return nil, fmt.Errorf("Error parsing auth_claims: %s", err)
+			}
+		}
+		token["sub"] = uid
+		request["auth"] = map[string]interface{}{
+			"uid":   uid,
+			"token": token,
+		}
+	}
+
+	if v := c["request_data"].(string); v != "" {
+		var data interface{}
+		if err := json.Unmarshal([]byte(v), &data); err != nil {
+			return nil, fmt.Errorf("Error parsing request_data: %s", err)
+		}
+		request["resource"] = map[string]interface{}{"data": data}
+	}
+
+	testCase := &rulesTestCase{
+		Expectation: c["expectation"].(string),
+		Request:     request,
+	}
+	if v := c["resource_data"].(string); v != "" {
+		var data interface{}
+		if err := json.Unmarshal([]byte(v), &data); err != nil {
+			return nil, fmt.Errorf("Error parsing resource_data: %s", err)
+		}
+		testCase.Resource = map[string]interface{}{"data": data}
+	}
+	return testCase, nil
+}
@@ -0,0 +1,164 @@
+package firebase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testRulesTestCases(cases ...map[string]interface{}) map[string]interface{} {
+	raw := testRulesetConfig("service cloud.firestore {\n  match /users/{uid} {\n    allow read: if request.auth.uid != null;\n  }\n}")
+	var testCases []interface{}
+	for _, c := range cases {
+		testCases = append(testCases, c)
+	}
+	raw["test_case"] = testCases
+	return raw
+}
+
+func TestDataSourceFirebaseRulesTest(t *testing.T) {
+	meta, closeServer := testAPIClient(t, newTestRulesServer(), rulesEndpoint)
+	defer closeServer()
+
+	ds := dataSourceFirebaseRulesTest()
+	d := schema.TestResourceDataRaw(t, ds.Schema, testRulesTestCases(
+		map[string]interface{}{
+			"path":        "/databases/(default)/documents/users/alice",
+			"method":      "get",
+			"auth_uid":    "alice",
+			"auth_claims": `{"admin": true}`,
+			"expectation": "ALLOW",
+		},
+		map[string]interface{}{
+			"path":        "/databases/(default)/documents/users/alice",
+			"method":      "get",
+			"expectation": "DENY",
+		},
+		map[string]interface{}{
+			"path":         "/databases/(default)/documents/users/alice",
+			"method":       "update",
+			"auth_uid":     "alice",
+			"request_data": `{"name": "Alice"}`,
+			"expectation":  "DENY",
+		},
+	))
+	if err := ds.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() == "" || d.Get("passed") != 3 {
+		t.Fatalf("incorrect result: %s %v", d.Id(), d.Get("passed"))
+	}
+}
+
+func TestDataSourceFirebaseRulesTest_failed(t *testing.T) {
+	meta, closeServer := testAPIClient(t, newTestRulesServer(), rulesEndpoint)
+	defer closeServer()
+
+	ds := dataSourceFirebaseRulesTest()
+	d := schema.TestResourceDataRaw(t, ds.Schema, testRulesTestCases(
+		map[string]interface{}{
+			"name":        "anonymous read",
+			"path":        "/databases/(default)/documents/users/alice",
+			"method":      "get",
+			"expectation": "ALLOW",
+		},
+		map[string]interface{}{
+			"path":        "/databases/(default)/documents/users/alice",
+			"method":      "list",
+			"auth_uid":    "alice",
+			"expectation": "ALLOW",
+		},
+		map[string]interface{}{
+			"path":        "/databases/(default)/documents/users/alice",
+			"method":      "get",
+			"auth_uid":    "bob",
+			"expectation": "DENY",
+		},
+	))
+	err := ds.Read(d, meta)
+	if err == nil {
+		t.Fatalf("expected failed test cases")
+	}
+	for _, expected := range []string{
+		"2 of 3 rules test cases failed",
+		"CASE            METHOD  PATH",
+		"anonymous read  get     /databases/(default)/documents/users/alice  ALLOW     firestore.rules:3:22: Null value error.",
+		"#3              get     /databases/(default)/documents/users/alice  DENY      expectation not met",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("error without %q:\n%s", expected, err)
+		}
+	}
+	if d.Id() != "" {
+		t.Fatalf("failed test cases were stored")
+	}
+}
+
+func TestDataSourceFirebaseRulesTest_emulator(t *testing.T) {
+	meta, closeServer := testAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API request with an emulator: %s %s", r.Method, r.URL)
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}), rulesEndpoint)
+	defer closeServer()
+
+	for env, content := range map[string]string{
+		"FIRESTORE_EMULATOR_HOST":        "service cloud.firestore {\n  match /users/{uid} {\n    allow read: if request.auth.uid != null;\n  }\n}",
+		"FIREBASE_STORAGE_EMULATOR_HOST": "rules_version = '2';\nservice firebase.storage {\n  match /b/{bucket}/o {\n    allow read: if request.auth.uid != null;\n  }\n}",
+	} {
+		var requests int
+		emulator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Method != "POST" || r.URL.Path != "/v1/projects/mock-project-id:test" {
+				t.Errorf("%s: incorrect emulator request: %s %s", env, r.Method, r.URL)
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer owner" {
+				t.Errorf("%s: incorrect authorization: %q", env, auth)
+			}
+			var body struct {
+				Source    rulesSource `json:"source"`
+				TestSuite struct {
+					TestCases []rulesTestCase `json:"testCases"`
+				} `json:"testSuite"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("%s: err: %s", env, err)
+			}
+			if len(body.Source.Files) != 1 || body.Source.Files[0].Content != content {
+				t.Errorf("%s: incorrect source: %#v", env, body.Source)
+			}
+			cases := body.TestSuite.TestCases
+			if len(cases) != 1 || cases[0].Expectation != "ALLOW" || cases[0].Request["path"] != "/databases/(default)/documents/users/alice" || cases[0].Request["auth"] == nil {
+				t.Errorf("%s: incorrect test cases: %#v", env, cases)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"testResults": [{"state": "SUCCESS"}]}`))
+		}))
+
+		os.Setenv(env, strings.TrimPrefix(emulator.URL, "http://"))
+		ds := dataSourceFirebaseRulesTest()
+		raw := testRulesetConfig(content)
+		raw["test_case"] = []interface{}{
+			map[string]interface{}{
+				"path":        "/databases/(default)/documents/users/alice",
+				"method":      "get",
+				"auth_uid":    "alice",
+				"expectation": "ALLOW",
+			},
+		}
+		d := schema.TestResourceDataRaw(t, ds.Schema, raw)
+		err := ds.Read(d, meta)
+		os.Unsetenv(env)
+		emulator.Close()
+		if err != nil {
+			t.Fatalf("%s: err: %s", env, err)
+		}
+		if requests != 1 || d.Get("passed") != 1 {
+			t.Fatalf("%s: emulator not used: %d %v", env, requests, d.Get("passed"))
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package firebase
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type webAppConfig struct {
+	APIKey            string `json:"apiKey"`
+	AuthDomain        string `json:"authDomain"`
+	DatabaseURL       string `json:"databaseURL"`
+	StorageBucket     string `json:"storageBucket"`
+	MessagingSenderID string `json:"messagingSenderId"`
+	LocationID        string `json:"locationId"`
+	MeasurementID     string `json:"measurementId"`
+}
+
+func dataSourceFirebaseWebAppConfig() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceFirebaseWebAppConfigRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"app_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"api_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"auth_domain": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"database_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"storage_bucket": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"messaging_sender_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"location_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"measurement_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceFirebaseWebAppConfigRead(d *schema.ResourceData, meta interface{}) error {
+	var config webAppConfig
+	if err := firebaseWebAppType.readConfig(d, meta, &config); err != nil {
+		return err
+	}
+
+	d.Set("api_key", config.APIKey)
+	d.Set("auth_domain", config.AuthDomain)
+	d.Set("database_url", config.DatabaseURL)
+	d.Set("storage_bucket", config.StorageBucket)
+	d.Set("messaging_sender_id", config.MessagingSenderID)
+	d.Set("location_id", config.LocationID)
+	d.Set("measurement_id", config.MeasurementID)
+
+	return nil
+}
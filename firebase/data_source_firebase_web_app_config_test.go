@@ -0,0 +1,39 @@
+package firebase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceFirebaseWebAppConfig(t *testing.T) {
+	fake := newTestAppsServer()
+	meta, closeServer := testAPIClient(t, fake, firebaseManagementEndpoint)
+	defer closeServer()
+	fake.apps["1:123456789012:web:0"] = map[string]interface{}{"state": "ACTIVE"}
+
+	r := dataSourceFirebaseWebAppConfig()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id": "1:123456789012:web:0",
+	})
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "1:123456789012:web:0" || d.Get("api_key") != "mock-api-key" {
+		t.Fatalf("incorrect config: %s %s", d.Id(), d.Get("api_key"))
+	}
+	if d.Get("auth_domain") != fmt.Sprintf("%s.firebaseapp.com", testProjectID) {
+		t.Fatalf("incorrect auth_domain: %s", d.Get("auth_domain"))
+	}
+	if d.Get("messaging_sender_id") != "123456789012" {
+		t.Fatalf("incorrect messaging_sender_id: %s", d.Get("messaging_sender_id"))
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id": "1:123456789012:web:1",
+	})
+	if err := r.Read(d, meta); err == nil {
+		t.Fatalf("expected error for a missing app")
+	}
+}
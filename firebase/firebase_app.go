@@ -0,0 +1,278 @@
+package firebase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// firebaseAppType is an app collection of the Management API, shared by
+// the web, Android and Apple app resources.
+type firebaseAppType struct {
+	// collection is the collection ID, e.g. webApps
+	collection string
+	// kind names the app in messages
+	kind string
+}
+
+var (
+	firebaseWebAppType     = firebaseAppType{collection: "webApps", kind: "web app"}
+	firebaseAndroidAppType = firebaseAppType{collection: "androidApps", kind: "Android app"}
+	firebaseAppleAppType   = firebaseAppType{collection: "iosApps", kind: "Apple app"}
+)
+
+// firebaseAppState holds the fields common to all app types.
+type firebaseAppState struct {
+	Name  string `json:"name"`
+	AppID string `json:"appId"`
+	State string `json:"state"`
+}
+
+func (t firebaseAppType) url(project, appID string) string {
+	return fmt.Sprintf("%s/projects/%s/%s/%s", firebaseManagementEndpoint, project, t.collection, appID)
+}
+
+// firebaseAppSchema returns the arguments shared by all app types merged
+// with the type specific schema s.
+func firebaseAppSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["project"] = projectSchema()
+	s["app_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	s["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	s["remove_immediately"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+	return s
+}
+
+// create registers app and waits until it is provisioned.
+func (t firebaseAppType) create(d *schema.ResourceData, meta interface{}, app interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Creating %s in project: %s", t.kind, client.ProjectID)
+
+	var op operation
+	url := fmt.Sprintf("%s/projects/%s/%s", firebaseManagementEndpoint, client.ProjectID, t.collection)
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, app, &op)
+	if err != nil {
+		return fmt.Errorf("Error creating %s: %s", t.kind, err)
+	}
+
+	var created firebaseAppState
+	err = waitForOperation(client.HTTP, firebaseManagementEndpoint, &op, d.Timeout(schema.TimeoutCreate), &created)
+	if err != nil {
+		return fmt.Errorf("Error creating %s: %s", t.kind, err)
+	}
+
+	d.SetId(created.AppID)
+	log.Printf("[INFO] App ID: %s", d.Id())
+	return nil
+}
+
+// read decodes the app into app. It removes the app from state and returns
+// false if it was removed.
+func (t firebaseAppType) read(d *schema.ResourceData, meta interface{}, app interface{}) (bool, error) {
+	log.Printf("[INFO] Reading %s: %s", t.kind, d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return false, err
+	}
+
+	var raw json.RawMessage
+	err = sendRequest(context.Background(), client.HTTP, "GET", t.url(client.ProjectID, d.Id()), nil, &raw)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] %s (%s) not found, removing from state", t.kind, d.Id())
+			d.SetId("")
+			return false, nil
+		}
+		return false, fmt.Errorf("Error reading %s (%s): %s", t.kind, d.Id(), err)
+	}
+
+	var state firebaseAppState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return false, err
+	}
+	if state.State == "DELETED" {
+		log.Printf("[WARN] %s (%s) was removed, removing from state", t.kind, d.Id())
+		d.SetId("")
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, app); err != nil {
+		return false, err
+	}
+
+	d.Set("app_id", state.AppID)
+	d.Set("name", state.Name)
+	return true, nil
+}
+
+// update patches the fields of app that changed, fields maps the schema to
+// the app's field names.
+func (t firebaseAppType) update(d *schema.ResourceData, meta interface{}, app interface{}, fields map[string]string) error {
+	var mask []string
+	for k, field := range fields {
+		if d.HasChange(k) {
+			mask = append(mask, field)
+		}
+	}
+	if len(mask) == 0 {
+		return nil
+	}
+	log.Printf("[INFO] Updating %s %s: %v", t.kind, d.Id(), mask)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s?updateMask=%s", t.url(client.ProjectID, d.Id()), strings.Join(mask, ","))
+
+	err = sendRequest(context.Background(), client.HTTP, "PATCH", url, app, nil)
+	if err != nil {
+		return fmt.Errorf("Error updating %s (%s): %s", t.kind, d.Id(), err)
+	}
+	return nil
+}
+
+// remove removes the app, which can be restored for 30 days by importing it
+// again unless remove_immediately is set.
+func (t firebaseAppType) remove(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Removing %s: %s", t.kind, d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"allowMissing": true,
+		"immediate":    d.Get("remove_immediately").(bool),
+	}
+
+	var op operation
+	err = sendRequest(context.Background(), client.HTTP, "POST", t.url(client.ProjectID, d.Id())+":remove", body, &op)
+	if err == nil {
+		err = waitForOperation(client.HTTP, firebaseManagementEndpoint, &op, d.Timeout(schema.TimeoutDelete), nil)
+	}
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error removing %s (%s): %s", t.kind, d.Id(), err)
+	}
+	return nil
+}
+
+// importState accepts an app ID or the app's name,
+// projects/<project>/<collection>/<app_id>. An app removed within the last
+// 30 days is restored.
+func (t firebaseAppType) importState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == t.collection:
+		d.Set("project", parts[1])
+		d.SetId(parts[3])
+	default:
+		return nil, fmt.Errorf("Import ID %q should be an app ID or projects/<project>/%s/<app_id>", d.Id(), t.collection)
+	}
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var state firebaseAppState
+	err = sendRequest(context.Background(), client.HTTP, "GET", t.url(client.ProjectID, d.Id()), nil, &state)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s (%s): %s", t.kind, d.Id(), err)
+	}
+	if state.State == "DELETED" {
+		log.Printf("[INFO] Restoring removed %s: %s", t.kind, d.Id())
+
+		var op operation
+		err = sendRequest(context.Background(), client.HTTP, "POST", t.url(client.ProjectID, d.Id())+":undelete", struct{}{}, &op)
+		if err == nil {
+			err = waitForOperation(client.HTTP, firebaseManagementEndpoint, &op, d.Timeout(schema.TimeoutCreate), nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error restoring %s (%s): %s", t.kind, d.Id(), err)
+		}
+	}
+
+	d.Set("remove_immediately", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// mobileAppConfig is the configuration artifact of an Android or Apple app,
+// google-services.json or GoogleService-Info.plist.
+type mobileAppConfig struct {
+	ConfigFilename     string `json:"configFilename"`
+	ConfigFileContents string `json:"configFileContents"`
+}
+
+// readConfig decodes the configuration of the app d's app_id into config.
+func (t firebaseAppType) readConfig(d *schema.ResourceData, meta interface{}, config interface{}) error {
+	appID := d.Get("app_id").(string)
+	log.Printf("[INFO] Reading config of %s: %s", t.kind, appID)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = sendRequest(context.Background(), client.HTTP, "GET", t.url(client.ProjectID, appID)+"/config", nil, config)
+	if err != nil {
+		return fmt.Errorf("Error reading config of %s (%s): %s", t.kind, appID, err)
+	}
+
+	d.SetId(appID)
+	return nil
+}
+
+// mobileAppConfigSchema is the schema of the Android and Apple app config
+// data sources.
+func mobileAppConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"project": projectSchema(),
+		"app_id": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"config_filename": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"config_file_contents": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+	}
+}
+
+// readMobileAppConfig returns the Read function of the Android or Apple app
+// config data source. The file contents are kept base64 encoded.
+func readMobileAppConfig(t firebaseAppType) schema.ReadFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		var config mobileAppConfig
+		if err := t.readConfig(d, meta, &config); err != nil {
+			return err
+		}
+
+		d.Set("config_filename", config.ConfigFilename)
+		d.Set("config_file_contents", config.ConfigFileContents)
+
+		return nil
+	}
+}
@@ -0,0 +1,286 @@
+package firebase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testAppsServer fakes the app collections of the Management API. Every
+// operation is done when it is returned.
+type testAppsServer struct {
+	sync.Mutex
+	apps    map[string]map[string]interface{}
+	certs   map[string]map[string]interface{}
+	masks   []string
+	removed []string
+}
+
+func newTestAppsServer() *testAppsServer {
+	return &testAppsServer{
+		apps:  make(map[string]map[string]interface{}),
+		certs: make(map[string]map[string]interface{}),
+	}
+}
+
+func (s *testAppsServer) done(w http.ResponseWriter, response interface{}) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":     "operations/mock",
+		"done":     true,
+		"response": response,
+	})
+}
+
+func (s *testAppsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	// /v1beta1/projects/<project>/<collection>[/<app_id>[:<verb>][/sha[/<sha_id>]]]
+	path := strings.TrimPrefix(r.URL.Path, "/v1beta1/")
+	parts := strings.Split(path, "/")
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if len(parts) == 3 && r.Method == "POST" {
+		appID := fmt.Sprintf("1:123456789012:%s:%d", parts[2], len(s.apps))
+		body["name"] = path + "/" + appID
+		body["appId"] = appID
+		body["projectId"] = parts[1]
+		body["state"] = "ACTIVE"
+		s.apps[appID] = body
+		s.done(w, body)
+		return
+	}
+	if len(parts) < 4 {
+		http.NotFound(w, r)
+		return
+	}
+
+	// App IDs contain colons, so only known verbs are split off
+	appID := strings.TrimSuffix(strings.TrimSuffix(parts[3], ":remove"), ":undelete")
+	app := s.apps[appID]
+	if app == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"NOT_FOUND"}}`)
+		return
+	}
+
+	switch {
+	case len(parts) == 4 && strings.HasSuffix(parts[3], ":remove"):
+		s.removed = append(s.removed, fmt.Sprintf("%s immediate=%v", appID, body["immediate"]))
+		app["state"] = "DELETED"
+		s.done(w, map[string]interface{}{})
+	case len(parts) == 4 && strings.HasSuffix(parts[3], ":undelete"):
+		app["state"] = "ACTIVE"
+		s.done(w, app)
+	case len(parts) == 4 && r.Method == "PATCH":
+		mask := r.URL.Query().Get("updateMask")
+		s.masks = append(s.masks, mask)
+		for _, field := range strings.Split(mask, ",") {
+			app[field] = body[field]
+		}
+		json.NewEncoder(w).Encode(app)
+	case len(parts) == 4:
+		json.NewEncoder(w).Encode(app)
+	case len(parts) == 5 && parts[4] == "config" && parts[2] == "webApps":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"projectId":         parts[1],
+			"appId":             appID,
+			"apiKey":            "mock-api-key",
+			"authDomain":        parts[1] + ".firebaseapp.com",
+			"databaseURL":       "https://" + parts[1] + ".firebaseio.com",
+			"storageBucket":     parts[1] + ".appspot.com",
+			"messagingSenderId": "123456789012",
+		})
+	case len(parts) == 5 && parts[4] == "config":
+		filename := "google-services.json"
+		if parts[2] == "iosApps" {
+			filename = "GoogleService-Info.plist"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"configFilename":     filename,
+			"configFileContents": base64.StdEncoding.EncodeToString([]byte(appID)),
+		})
+	case len(parts) == 5 && r.Method == "POST":
+		body["name"] = fmt.Sprintf("%s/sha/%d", strings.Join(parts[:4], "/"), len(s.certs))
+		s.certs[body["name"].(string)] = body
+		json.NewEncoder(w).Encode(body)
+	case len(parts) == 5:
+		var certs []map[string]interface{}
+		for name, cert := range s.certs {
+			if strings.HasPrefix(name, path+"/") {
+				certs = append(certs, cert)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"certificates": certs})
+	case len(parts) == 6 && r.Method == "DELETE":
+		delete(s.certs, path)
+		fmt.Fprint(w, `{}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *testAppsServer) hashes() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	var hashes []string
+	for _, cert := range s.certs {
+		hashes = append(hashes, fmt.Sprintf("%s:%s", cert["certType"], cert["shaHash"]))
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+func TestResourceFirebaseWebApp(t *testing.T) {
+	fake := newTestAppsServer()
+	meta, closeServer := testAPIClient(t, fake, firebaseManagementEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseWebApp()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"display_name": "Console",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	appID := d.Id()
+	if appID != "1:123456789012:webApps:0" || d.Get("app_id") != appID {
+		t.Fatalf("incorrect app ID: %q", appID)
+	}
+	if d.Get("name") != fmt.Sprintf("projects/%s/webApps/%s", testProjectID, appID) {
+		t.Fatalf("incorrect name: %q", d.Get("name"))
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"display_name": "Admin console",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(d.State(), terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.masks) != "[displayName]" || state.Attributes["display_name"] != "Admin console" {
+		t.Fatalf("display_name was not updated: %v", fake.masks)
+	}
+
+	// Removed apps are gone from state but can be restored by importing them
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.removed) != fmt.Sprintf("[%s immediate=false]", appID) {
+		t.Fatalf("app was not removed: %v", fake.removed)
+	}
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("removed app was kept in state")
+	}
+
+	id := fmt.Sprintf("projects/%s/webApps/%s", testProjectID, appID)
+	states, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if states[0].Id() != appID || fake.apps[appID]["state"] != "ACTIVE" {
+		t.Fatalf("removed app was not restored: %s %s", states[0].Id(), fake.apps[appID]["state"])
+	}
+	if _, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: "projects/p/androidApps/1"}), meta); err == nil {
+		t.Fatalf("expected error for an app of another type")
+	}
+}
+
+func TestResourceFirebaseAndroidApp(t *testing.T) {
+	fake := newTestAppsServer()
+	meta, closeServer := testAPIClient(t, fake, firebaseManagementEndpoint)
+	defer closeServer()
+
+	sha1 := "DA:39:A3:EE:5E:6B:4B:0D:32:55:BF:EF:95:60:18:90:AF:D8:07:09"
+	sha256 := strings.Repeat("ab", 32)
+
+	r := resourceFirebaseAndroidApp()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"package_name":  "com.example.app",
+		"sha1_hashes":   []interface{}{sha1},
+		"sha256_hashes": []interface{}{sha256},
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := "[SHA_1:da39a3ee5e6b4b0d3255bfef95601890afd80709 SHA_256:" + sha256 + "]"
+	if fmt.Sprint(fake.hashes()) != expected {
+		t.Fatalf("incorrect certificates: %v", fake.hashes())
+	}
+	if d.Get("sha1_hashes").(*schema.Set).Len() != 1 || !d.Get("sha1_hashes").(*schema.Set).Contains(sha1) {
+		t.Fatalf("incorrect sha1_hashes: %v", d.Get("sha1_hashes"))
+	}
+
+	// Fingerprints are compared in their normalized form
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"package_name":  "com.example.app",
+		"sha1_hashes":   []interface{}{strings.ToLower(sha1)},
+		"sha256_hashes": []interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(d.State(), terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(d.State(), diff, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.hashes()) != "[SHA_1:da39a3ee5e6b4b0d3255bfef95601890afd80709]" {
+		t.Fatalf("incorrect certificates after update: %v", fake.hashes())
+	}
+}
+
+func TestResourceFirebaseAppleApp(t *testing.T) {
+	fake := newTestAppsServer()
+	meta, closeServer := testAPIClient(t, fake, firebaseManagementEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseAppleApp()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"bundle_id":          "com.example.app",
+		"app_store_id":       "123456789",
+		"remove_immediately": true,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("bundle_id") != "com.example.app" || d.Get("app_store_id") != "123456789" {
+		t.Fatalf("incorrect app: %s %s", d.Get("bundle_id"), d.Get("app_store_id"))
+	}
+
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.removed) != fmt.Sprintf("[%s immediate=true]", d.Id()) {
+		t.Fatalf("app was not removed immediately: %v", fake.removed)
+	}
+}
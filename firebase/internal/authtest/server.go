@@ -0,0 +1,428 @@
+// Package authtest provides an in-memory stand-in for the Identity Toolkit
+// relyingparty API used by the Firebase Admin SDK auth client, so resources
+// can be tested without a live project.
+package authtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	identitytoolkit "google.golang.org/api/identitytoolkit/v3"
+)
+
+// RelyingPartyEndpoint is the base URL of the API the Admin SDK calls.
+const RelyingPartyEndpoint = "https://www.googleapis.com/identitytoolkit/v3/relyingparty"
+
+// Server is a fake Identity Toolkit server. It also answers OAuth2 token
+// requests on /token, so service account keys can point their token_uri at
+// it.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	users       map[string]*identitytoolkit.UserInfo
+	calls       []string
+	revocations map[string]int
+}
+
+// NewServer starts a fake Identity Toolkit server. Callers should Close it
+// when done.
+func NewServer() *Server {
+	s := &Server{
+		users:       make(map[string]*identitytoolkit.UserInfo),
+		revocations: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"mock-access-token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/signupNewUser", s.handle(s.signupNewUser))
+	mux.HandleFunc("/getAccountInfo", s.handle(s.getAccountInfo))
+	mux.HandleFunc("/setAccountInfo", s.handle(s.setAccountInfo))
+	mux.HandleFunc("/deleteAccount", s.handle(s.deleteAccount))
+	mux.HandleFunc("/downloadAccount", s.handle(s.downloadAccount))
+	mux.HandleFunc("/uploadAccount", s.handle(s.uploadAccount))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Endpoints returns the endpoint overrides that redirect the Admin SDK to s.
+func (s *Server) Endpoints() map[string]string {
+	return map[string]string{RelyingPartyEndpoint: s.URL}
+}
+
+// TokenURI is the OAuth2 token endpoint of s.
+func (s *Server) TokenURI() string {
+	return s.URL + "/token"
+}
+
+// User returns a copy of the stored user with the given UID.
+func (s *Server) User(uid string) (identitytoolkit.UserInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[uid]
+	if !ok {
+		return identitytoolkit.UserInfo{}, false
+	}
+	return *s.withProviders(u), true
+}
+
+// DeleteUser removes a user behind the client's back, e.g. to simulate a
+// deletion from the Firebase console.
+func (s *Server) DeleteUser(uid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.users, uid)
+}
+
+// LinkProvider links a provider identity to the user with the given UID, or
+// updates its profile when already linked, as a sign in with the provider
+// does.
+func (s *Server) LinkProvider(uid string, info *identitytoolkit.UserInfoProviderUserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[uid]
+	if !ok {
+		return
+	}
+	for i, p := range u.ProviderUserInfo {
+		if p.ProviderId == info.ProviderId && p.RawId == info.RawId {
+			u.ProviderUserInfo[i] = info
+			return
+		}
+	}
+	u.ProviderUserInfo = append(u.ProviderUserInfo, info)
+}
+
+// Calls returns the relyingparty methods called so far, in order.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+// Revocations returns how many times the refresh tokens of the user with the
+// given UID were revoked.
+func (s *Server) Revocations(uid string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revocations[uid]
+}
+
+// apiError is returned in the same shape as Google API errors, so the SDK
+// maps the message to its error codes.
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(message string) error {
+	return &apiError{http.StatusBadRequest, message}
+}
+
+// handle decodes the request body into a generic map, calls f with the
+// store locked and encodes its result.
+func (s *Server) handle(f func(body []byte, fields map[string]interface{}) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		s.calls = append(s.calls, strings.TrimPrefix(r.URL.Path, "/"))
+		res, err := f(raw, fields)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			code := http.StatusInternalServerError
+			if e, ok := err.(*apiError); ok {
+				code = e.code
+			}
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"code": code, "message": err.Error()},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func (s *Server) signupNewUser(body []byte, _ map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartySignupNewUserRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	uid := req.LocalId
+	if uid == "" {
+		uid = fmt.Sprintf("uid-%d", time.Now().UnixNano())
+	}
+	if _, ok := s.users[uid]; ok {
+		return nil, badRequest("DUPLICATE_LOCAL_ID")
+	}
+	if err := s.checkUnique(uid, req.Email, req.PhoneNumber); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	u := &identitytoolkit.UserInfo{
+		LocalId:       uid,
+		Email:         req.Email,
+		EmailVerified: req.EmailVerified,
+		DisplayName:   req.DisplayName,
+		PhotoUrl:      req.PhotoUrl,
+		PhoneNumber:   req.PhoneNumber,
+		Disabled:      req.Disabled,
+		CreatedAt:     now.UnixNano() / int64(time.Millisecond),
+		ValidSince:    now.Unix(),
+	}
+	setPassword(u, req.Password)
+	s.users[uid] = u
+
+	return &identitytoolkit.SignupNewUserResponse{LocalId: uid}, nil
+}
+
+func (s *Server) getAccountInfo(body []byte, _ map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartyGetAccountInfoRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	res := &identitytoolkit.GetAccountInfoResponse{}
+	for _, u := range s.sortedUsers() {
+		if contains(req.LocalId, u.LocalId) || contains(req.Email, u.Email) || contains(req.PhoneNumber, u.PhoneNumber) {
+			res.Users = append(res.Users, s.withProviders(u))
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) setAccountInfo(body []byte, fields map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartySetAccountInfoRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	u, ok := s.users[req.LocalId]
+	if !ok {
+		return nil, badRequest("USER_NOT_FOUND")
+	}
+	if err := s.checkUnique(u.LocalId, req.Email, req.PhoneNumber); err != nil {
+		return nil, err
+	}
+
+	// Only fields present in the request are updated
+	if _, ok := fields["email"]; ok {
+		u.Email = req.Email
+	}
+	if _, ok := fields["emailVerified"]; ok {
+		u.EmailVerified = req.EmailVerified
+	}
+	if _, ok := fields["displayName"]; ok {
+		u.DisplayName = req.DisplayName
+	}
+	if _, ok := fields["photoUrl"]; ok {
+		u.PhotoUrl = req.PhotoUrl
+	}
+	if _, ok := fields["phoneNumber"]; ok {
+		u.PhoneNumber = req.PhoneNumber
+	}
+	if _, ok := fields["disableUser"]; ok {
+		u.Disabled = req.DisableUser
+	}
+	if _, ok := fields["customAttributes"]; ok {
+		u.CustomAttributes = req.CustomAttributes
+		if u.CustomAttributes == "{}" {
+			u.CustomAttributes = ""
+		}
+	}
+	if _, ok := fields["validSince"]; ok {
+		u.ValidSince = req.ValidSince
+		s.revocations[u.LocalId]++
+	}
+	if req.Password != "" {
+		setPassword(u, req.Password)
+	}
+	for _, attr := range req.DeleteAttribute {
+		switch attr {
+		case "DISPLAY_NAME":
+			u.DisplayName = ""
+		case "PHOTO_URL":
+			u.PhotoUrl = ""
+		}
+	}
+	for _, provider := range req.DeleteProvider {
+		if provider == "phone" {
+			u.PhoneNumber = ""
+		}
+	}
+
+	return &identitytoolkit.SetAccountInfoResponse{LocalId: u.LocalId, Email: u.Email}, nil
+}
+
+func (s *Server) deleteAccount(body []byte, _ map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartyDeleteAccountRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	if _, ok := s.users[req.LocalId]; !ok {
+		return nil, badRequest("USER_NOT_FOUND")
+	}
+	delete(s.users, req.LocalId)
+	return &identitytoolkit.DeleteAccountResponse{}, nil
+}
+
+func (s *Server) downloadAccount(body []byte, _ map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartyDownloadAccountRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	max := int(req.MaxResults)
+	if max <= 0 {
+		max = 1000
+	}
+
+	// Pages are keyed by the last UID returned
+	res := &identitytoolkit.DownloadAccountResponse{}
+	for _, u := range s.sortedUsers() {
+		if u.LocalId <= req.NextPageToken {
+			continue
+		}
+		if len(res.Users) == max {
+			res.NextPageToken = res.Users[max-1].LocalId
+			break
+		}
+		res.Users = append(res.Users, s.withProviders(u))
+	}
+	return res, nil
+}
+
+func (s *Server) uploadAccount(body []byte, _ map[string]interface{}) (interface{}, error) {
+	var req identitytoolkit.IdentitytoolkitRelyingpartyUploadAccountRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	// Like the backend, imports overwrite existing users with the same UID
+	res := &identitytoolkit.UploadAccountResponse{}
+	for i, u := range req.Users {
+		if err := s.checkUnique(u.LocalId, u.Email, u.PhoneNumber); err != nil {
+			res.Error = append(res.Error, &identitytoolkit.UploadAccountResponseError{
+				Index:   int64(i),
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		imported := *u
+		if imported.CreatedAt == 0 {
+			imported.CreatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+		}
+		if imported.ValidSince == 0 {
+			imported.ValidSince = time.Now().Unix()
+		}
+		s.users[u.LocalId] = &imported
+	}
+	return res, nil
+}
+
+// checkUnique fails if another user than uid has the email or phone number.
+func (s *Server) checkUnique(uid, email, phone string) error {
+	for _, u := range s.users {
+		if u.LocalId == uid {
+			continue
+		}
+		if email != "" && u.Email == email {
+			return badRequest("EMAIL_EXISTS")
+		}
+		if phone != "" && u.PhoneNumber == phone {
+			return badRequest("PHONE_NUMBER_EXISTS")
+		}
+	}
+	return nil
+}
+
+func (s *Server) sortedUsers() []*identitytoolkit.UserInfo {
+	users := make([]*identitytoolkit.UserInfo, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].LocalId < users[j].LocalId })
+	return users
+}
+
+// withProviders returns a copy of u listing the password and phone
+// providers the backend derives from the account, followed by any imported
+// federated providers.
+func (s *Server) withProviders(u *identitytoolkit.UserInfo) *identitytoolkit.UserInfo {
+	c := *u
+	var providers []*identitytoolkit.UserInfoProviderUserInfo
+	if c.Email != "" && c.PasswordHash != "" {
+		providers = append(providers, &identitytoolkit.UserInfoProviderUserInfo{
+			ProviderId:  "password",
+			RawId:       c.Email,
+			Email:       c.Email,
+			DisplayName: c.DisplayName,
+			PhotoUrl:    c.PhotoUrl,
+		})
+	}
+	if c.PhoneNumber != "" {
+		providers = append(providers, &identitytoolkit.UserInfoProviderUserInfo{
+			ProviderId:  "phone",
+			RawId:       c.PhoneNumber,
+			PhoneNumber: c.PhoneNumber,
+		})
+	}
+	for _, p := range u.ProviderUserInfo {
+		if p.ProviderId != "password" && p.ProviderId != "phone" {
+			providers = append(providers, p)
+		}
+	}
+	c.ProviderUserInfo = providers
+	return &c
+}
+
+// setPassword stores a stand-in hash, the fake never verifies passwords.
+func setPassword(u *identitytoolkit.UserInfo, password string) {
+	if password == "" {
+		return
+	}
+	u.PasswordHash = base64.RawURLEncoding.EncodeToString([]byte("hash:" + password))
+	u.Salt = base64.RawURLEncoding.EncodeToString([]byte("salt:" + u.LocalId))
+	u.PasswordUpdatedAt = float64(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+func contains(values []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package firebase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveLogKeys are field and attribute names whose values never reach
+// the logs, compared after normalizeLogKey.
+var sensitiveLogKeys = map[string]bool{
+	"accesstoken":       true,
+	"generatedpassword": true,
+	"idtoken":           true,
+	"link":              true,
+	"oobcode":           true,
+	"ooblink":           true,
+	"password":          true,
+	"passwordhash":      true,
+	"passwordsalt":      true,
+	"privatekey":        true,
+	"rawpassword":       true,
+	"refreshtoken":      true,
+	"salt":              true,
+	"saltseparator":     true,
+	"signerkey":         true,
+	"token":             true,
+	"tokens":            true,
+}
+
+// claimsLogKeys hold custom claims, whose names are logged but not their
+// values.
+var claimsLogKeys = map[string]bool{
+	"claims":           true,
+	"customattributes": true,
+	"customclaims":     true,
+}
+
+var (
+	phoneNumberPattern = regexp.MustCompile(`\+[1-9][0-9]{5,14}`)
+	emailPattern       = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+)
+
+// redactLogEmails masks email addresses in addition to phone numbers. It
+// is enabled by setting FIREBASE_LOG_REDACT_EMAILS.
+var redactLogEmails = os.Getenv("FIREBASE_LOG_REDACT_EMAILS") != ""
+
+// logf is log.Printf with every argument redacted, see redactLogValue.
+func logf(format string, v ...interface{}) {
+	args := make([]interface{}, len(v))
+	for i, arg := range v {
+		args[i] = redactLogValue(arg)
+	}
+	log.Printf(format, args...)
+}
+
+// redactLogValue returns a loggable copy of v. Strings and numbers are
+// masked, resource data is reduced to its redacted attributes and any other
+// value is rendered as redacted JSON.
+func redactLogValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, bool, int, int64, float64:
+		return v
+	case error:
+		return maskLogString(v.Error())
+	case string:
+		return maskLogString(v)
+	case *schema.ResourceData:
+		return redactResourceData(v)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<%T>", v)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return fmt.Sprintf("<%T>", v)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactLogTree("", generic)); err != nil {
+		return fmt.Sprintf("<%T>", v)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// redactResourceData renders the attributes of d, which are flattened keys
+// such as federated_identity.1234.email, in a stable order.
+func redactResourceData(d *schema.ResourceData) string {
+	state := d.State()
+	if state == nil {
+		return fmt.Sprintf("{id: %s}", maskLogString(d.Id()))
+	}
+
+	keys := make([]string, 0, len(state.Attributes))
+	for k := range state.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		var v interface{} = state.Attributes[k]
+		for _, name := range strings.Split(k, ".") {
+			if sensitiveLogKeys[normalizeLogKey(name)] {
+				v = redactedValue
+			}
+		}
+		if v != redactedValue {
+			v = redactLogTree(k[strings.LastIndex(k, ".")+1:], v)
+		}
+		attrs = append(attrs, fmt.Sprintf("%s: %v", k, v))
+	}
+	return fmt.Sprintf("{id: %s, %s}", maskLogString(state.ID), strings.Join(attrs, ", "))
+}
+
+// redactLogTree redacts a JSON like value found under key.
+func redactLogTree(key string, v interface{}) interface{} {
+	k := normalizeLogKey(key)
+	if sensitiveLogKeys[k] {
+		if s, ok := v.(string); ok && s == "" {
+			return s
+		}
+		return redactedValue
+	}
+	if claimsLogKeys[k] {
+		return redactLogClaims(v)
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = redactLogTree(k, e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = redactLogTree(key, e)
+		}
+		return l
+	case string:
+		return maskLogString(v)
+	}
+	return v
+}
+
+// redactLogClaims keeps the names of custom claims, which may be a JSON
+// string, and drops their values.
+func redactLogClaims(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		if s == "" {
+			return s
+		}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return redactedValue
+		}
+	}
+	claims, ok := v.(map[string]interface{})
+	if !ok {
+		return redactedValue
+	}
+	m := make(map[string]interface{}, len(claims))
+	for k := range claims {
+		m[k] = redactedValue
+	}
+	return m
+}
+
+// maskLogString masks phone numbers, keeping their last two digits, and
+// emails if redactLogEmails is set.
+func maskLogString(s string) string {
+	s = phoneNumberPattern.ReplaceAllStringFunc(s, func(phone string) string {
+		return "+" + strings.Repeat("*", len(phone)-3) + phone[len(phone)-2:]
+	})
+	if redactLogEmails {
+		s = emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+			return email[:1] + "***" + email[strings.Index(email, "@"):]
+		})
+	}
+	return s
+}
+
+func normalizeLogKey(k string) string {
+	return strings.ToLower(strings.Replace(k, "_", "", -1))
+}
@@ -0,0 +1,110 @@
+package firebase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"firebase.google.com/go/auth"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestLogfRedactsSensitiveValues(t *testing.T) {
+	const (
+		password     = "correct-horse-battery-staple"
+		passwordHash = "aGFzaC1vZi10aGUtcGFzc3dvcmQ"
+		claimValue   = "top-secret-role"
+	)
+	secrets := []string{
+		password,
+		passwordHash,
+		claimValue,
+		testUser.UserInfo.PhoneNumber,
+		testUser.UserInfo.Email,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signupNewUser", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"localId":%q}`, testUser.UserInfo.UID)
+	})
+	mux.HandleFunc("/getAccountInfo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"users": []map[string]interface{}{{
+				"localId":          testUser.UserInfo.UID,
+				"email":            testUser.UserInfo.Email,
+				"phoneNumber":      testUser.UserInfo.PhoneNumber,
+				"passwordHash":     passwordHash,
+				"customAttributes": fmt.Sprintf(`{"role":%q}`, claimValue),
+			}},
+		})
+	})
+	mux.HandleFunc("/setAccountInfo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"localId":%q}`, testUser.UserInfo.UID)
+	})
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		identityToolkitEndpoint: srv.URL,
+	})
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	redactLogEmails = true
+	defer func() { redactLogEmails = false }()
+
+	r := resourceFirebaseUser()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"uid":           testUser.UserInfo.UID,
+		"email":         testUser.UserInfo.Email,
+		"phone_number":  testUser.UserInfo.PhoneNumber,
+		"photo_url":     testUser.UserInfo.PhotoURL,
+		"display_name":  testUser.UserInfo.DisplayName,
+		"password":      password,
+		"custom_claims": fmt.Sprintf(`{"role":%q}`, claimValue),
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	logf("[DEBUG] %v %v %s", &auth.ExportedUserRecord{
+		UserRecord:   &auth.UserRecord{UserInfo: &auth.UserInfo{Email: testUser.UserInfo.Email}},
+		PasswordHash: passwordHash,
+	}, map[string]interface{}{"password": password}, "phone:"+testUser.UserInfo.PhoneNumber)
+
+	out := buf.String()
+	if !strings.Contains(out, "Updating user resource") || !strings.Contains(out, "UserRecord:") {
+		t.Fatalf("expected resource and user record logs, got:\n%s", out)
+	}
+	if !strings.Contains(out, redactedValue) || !strings.Contains(out, `"role":"<redacted>"`) {
+		t.Fatalf("expected redacted values, got:\n%s", out)
+	}
+	for _, secret := range secrets {
+		if strings.Contains(out, secret) {
+			t.Fatalf("log output contains %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestMaskLogString(t *testing.T) {
+	cases := map[string]string{
+		"phone:+14155552671":        "phone:+*********71",
+		"no sensitive content here": "no sensitive content here",
+		"john.doe@example.com":      "j***@example.com",
+	}
+
+	redactLogEmails = true
+	defer func() { redactLogEmails = false }()
+
+	for in, expected := range cases {
+		if out := maskLogString(in); out != expected {
+			t.Fatalf("expected %q for %q, got %q", expected, in, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package firebase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+const firebaseManagementEndpoint = "https://firebase.googleapis.com/v1beta1"
+
+// pollDelay and pollMinTimeout pace the waits for long running changes.
+// Offline tests shorten them.
+var (
+	pollDelay      = 1 * time.Second
+	pollMinTimeout = 2 * time.Second
+)
+
+// operation is a google.longrunning.Operation returned by the Management
+// API and similar REST APIs.
+type operation struct {
+	Name     string          `json:"name"`
+	Done     bool            `json:"done"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	Error    *operationError `json:"error,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+type operationError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *operationError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// waitForOperation polls op, whose name is relative to endpoint, until it is
+// done and decodes its response into result.
+func waitForOperation(client *http.Client, endpoint string, op *operation, timeout time.Duration, result interface{}) error {
+	return waitForOperationWithProgress(client, endpoint, op, timeout, result, nil)
+}
+
+// waitForOperationWithProgress is waitForOperation, calling progress with
+// the operation after every poll.
+func waitForOperationWithProgress(client *http.Client, endpoint string, op *operation, timeout time.Duration, result interface{}, progress func(*operation)) error {
+	log.Printf("[DEBUG] Waiting for operation (%s) to be done", op.Name)
+
+	refresh := operationStateRefreshFunc(client, endpoint, op)
+	if progress != nil {
+		poll := refresh
+		refresh = func() (interface{}, string, error) {
+			v, state, err := poll()
+			if err == nil {
+				progress(op)
+			}
+			return v, state, err
+		}
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"running"},
+		Target:     []string{"done"},
+		Refresh:    refresh,
+		Timeout:    timeout,
+		Delay:      pollDelay,
+		MinTimeout: pollMinTimeout,
+	}
+	if !op.Done {
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("Error waiting for operation (%s) to be done: %s", op.Name, err)
+		}
+	}
+
+	if op.Error != nil {
+		return fmt.Errorf("Operation (%s) failed: %s", op.Name, op.Error)
+	}
+	if result == nil || len(op.Response) == 0 {
+		return nil
+	}
+	return json.Unmarshal(op.Response, result)
+}
+
+// operationStateRefreshFunc updates op in place, so the last poll is kept.
+func operationStateRefreshFunc(client *http.Client, endpoint string, op *operation) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		url := fmt.Sprintf("%s/%s", endpoint, op.Name)
+		if err := sendRequest(context.Background(), client, "GET", url, nil, op); err != nil {
+			return nil, "", err
+		}
+		if !op.Done {
+			return op, "running", nil
+		}
+		return op, "done", nil
+	}
+}
@@ -0,0 +1,26 @@
+package firebase
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const passwordCharset = "abcdefghijklmnopqrstuvwxyz" +
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+	"0123456789" +
+	"!#$%&*+-=?@^_~"
+
+// generatePassword returns a random password of the given length drawn from
+// passwordCharset using a cryptographically secure source.
+func generatePassword(length int) (string, error) {
+	max := big.NewInt(int64(len(passwordCharset)))
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = passwordCharset[n.Int64()]
+	}
+	return string(b), nil
+}
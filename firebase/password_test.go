@@ -0,0 +1,28 @@
+package firebase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, length := range []int{6, 32, 128} {
+		pw, err := generatePassword(length)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if len(pw) != length {
+			t.Fatalf("expected length %d, got %d", length, len(pw))
+		}
+		for _, c := range pw {
+			if !strings.ContainsRune(passwordCharset, c) {
+				t.Fatalf("unexpected character %q in %q", c, pw)
+			}
+		}
+		if seen[pw] {
+			t.Fatalf("duplicate password generated: %q", pw)
+		}
+		seen[pw] = true
+	}
+}
@@ -0,0 +1,36 @@
+package firebase
+
+import (
+	"firebase.google.com/go/messaging"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// projectSchema is the optional project argument shared by every resource
+// and data source. It defaults to the provider's project.
+func projectSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ForceNew: true,
+	}
+}
+
+// projectClient returns the client of the resource's project.
+func projectClient(d *schema.ResourceData, meta interface{}) (Client, error) {
+	client, err := meta.(Client).forProject(d.Get("project").(string))
+	if err != nil {
+		return Client{}, err
+	}
+	d.Set("project", client.ProjectID)
+	return client, nil
+}
+
+// messagingClient returns the messaging client of the resource's project.
+func messagingClient(d *schema.ResourceData, meta interface{}) (*messaging.Client, error) {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	return client.Messaging, nil
+}
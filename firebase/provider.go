@@ -14,9 +14,48 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("FIREBASE_SERVICE_ACCOUNT_KEY", nil),
 				Description: descriptions["service_account_key"],
 			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
+					"FIREBASE_PROJECT",
+					"GOOGLE_CLOUD_PROJECT",
+				}, nil),
+				Description: descriptions["project"],
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"firebase_android_app_config": dataSourceFirebaseAndroidAppConfig(),
+			"firebase_apple_app_config":   dataSourceFirebaseAppleAppConfig(),
+			"firebase_custom_token":       dataSourceFirebaseCustomToken(),
+			"firebase_id_token_claims":    dataSourceFirebaseIDTokenClaims(),
+			"firebase_rules_test":         dataSourceFirebaseRulesTest(),
+			"firebase_web_app_config":     dataSourceFirebaseWebAppConfig(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"firebase_user": resourceFirebaseUser(),
+			"firebase_android_app":                  resourceFirebaseAndroidApp(),
+			"firebase_apple_app":                    resourceFirebaseAppleApp(),
+			"firebase_auth_tenant":                  resourceFirebaseAuthTenant(),
+			"firebase_firestore_field_override":     resourceFirebaseFirestoreFieldOverride(),
+			"firebase_firestore_index":              resourceFirebaseFirestoreIndex(),
+			"firebase_hosting_channel":              resourceFirebaseHostingChannel(),
+			"firebase_hosting_custom_domain":        resourceFirebaseHostingCustomDomain(),
+			"firebase_hosting_release":              resourceFirebaseHostingRelease(),
+			"firebase_hosting_site":                 resourceFirebaseHostingSite(),
+			"firebase_hosting_version":              resourceFirebaseHostingVersion(),
+			"firebase_instance_id_deletion":         resourceFirebaseInstanceIDDeletion(),
+			"firebase_messaging_message":            resourceFirebaseMessagingMessage(),
+			"firebase_messaging_topic_subscription": resourceFirebaseMessagingTopicSubscription(),
+			"firebase_project":                      resourceFirebaseProject(),
+			"firebase_remote_config_parameter":      resourceFirebaseRemoteConfigParameter(),
+			"firebase_remote_config_template":       resourceFirebaseRemoteConfigTemplate(),
+			"firebase_rules_release":                resourceFirebaseRulesRelease(),
+			"firebase_rules_ruleset":                resourceFirebaseRulesRuleset(),
+			"firebase_user":                         resourceFirebaseUser(),
+			"firebase_user_action_link":             resourceFirebaseUserActionLink(),
+			"firebase_user_session_revocation":      resourceFirebaseUserSessionRevocation(),
+			"firebase_users_backup":                 resourceFirebaseUsersBackup(),
+			"firebase_web_app":                      resourceFirebaseWebApp(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -26,14 +65,44 @@ var descriptions map[string]string
 
 func init() {
 	descriptions = map[string]string{
-		"service_account_key": "Firebase Admin SDK Service Account Key File",
-		"firebase_user":       "Firebase User",
+		"service_account_key":                   "Firebase Admin SDK Service Account Key File",
+		"project":                               "Default project, the service account's project if unset",
+		"firebase_user":                         "Firebase User",
+		"firebase_project":                      "Firebase enablement of a Google Cloud project",
+		"firebase_web_app":                      "Firebase web app registration",
+		"firebase_android_app":                  "Firebase Android app registration",
+		"firebase_apple_app":                    "Firebase Apple app registration",
+		"firebase_android_app_config":           "Firebase Android app google-services.json",
+		"firebase_apple_app_config":             "Firebase Apple app GoogleService-Info.plist",
+		"firebase_web_app_config":               "Firebase web app configuration",
+		"firebase_hosting_site":                 "Firebase Hosting site",
+		"firebase_hosting_channel":              "Firebase Hosting preview channel",
+		"firebase_hosting_version":              "Firebase Hosting version of uploaded files",
+		"firebase_hosting_release":              "Firebase Hosting release of a version",
+		"firebase_hosting_custom_domain":        "Firebase Hosting custom domain",
+		"firebase_remote_config_template":       "Firebase Remote Config template",
+		"firebase_remote_config_parameter":      "Firebase Remote Config parameter",
+		"firebase_rules_ruleset":                "Firebase security rules ruleset",
+		"firebase_rules_release":                "Firebase security rules release of a ruleset",
+		"firebase_rules_test":                   "Firebase security rules test cases",
+		"firebase_firestore_index":              "Cloud Firestore composite index",
+		"firebase_firestore_field_override":     "Cloud Firestore single field index and TTL policy override",
+		"firebase_auth_tenant":                  "Identity Platform tenant",
+		"firebase_custom_token":                 "Firebase custom authentication token",
+		"firebase_id_token_claims":              "Firebase ID token verification",
+		"firebase_user_action_link":             "Firebase User email action link",
+		"firebase_user_session_revocation":      "Firebase User refresh token revocation",
+		"firebase_users_backup":                 "Firebase Users backup file",
+		"firebase_instance_id_deletion":         "Firebase Instance ID deletion",
+		"firebase_messaging_message":            "Firebase Cloud Messaging message",
+		"firebase_messaging_topic_subscription": "Firebase Cloud Messaging topic subscription",
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
 		ServiceAccountKey: d.Get("service_account_key").(string),
+		Project:           d.Get("project").(string),
 	}
 	return config.Client()
 }
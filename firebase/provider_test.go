@@ -1,10 +1,24 @@
 package firebase
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/eliaszs/terraform-provider-firebase/firebase/internal/authtest"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -43,6 +57,11 @@ func init() {
 	for k, v := range testAccProviders {
 		testAccProvidersWithTLS[k] = v
 	}
+
+	// Fakes need no backoff, unlike live projects
+	if os.Getenv(resource.TestEnvVar) == "" {
+		pollDelay, pollMinTimeout = time.Millisecond, time.Millisecond
+	}
 }
 
 func TestProvider(t *testing.T) {
@@ -83,3 +102,115 @@ func testAccCheckWithProviders(f func(*terraform.State, *schema.Provider) error,
 		return nil
 	}
 }
+
+const testProjectID = "mock-project-id"
+
+// testServiceAccountKey writes a service account key file backed by a freshly
+// generated RSA key, so tests can sign tokens without a live project. OAuth2
+// access tokens are requested from tokenURI.
+func testServiceAccountKey(t *testing.T, tokenURI string) (string, *rsa.PrivateKey) {
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	key, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     testProjectID,
+		"private_key_id": "mock-key-id",
+		"private_key": string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(pk),
+		})),
+		"client_email": "mock-email@mock-project.iam.gserviceaccount.com",
+		"client_id":    "1234567890",
+		"token_uri":    tokenURI,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "terraform-provider-firebase")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	path := filepath.Join(dir, "service_account_key.json")
+	if err := ioutil.WriteFile(path, key, 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return path, pk
+}
+
+// testServer starts a local server that answers OAuth2 token requests in
+// addition to the handlers registered on mux.
+func testServer(mux *http.ServeMux) *httptest.Server {
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"mock-access-token","token_type":"Bearer","expires_in":3600}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+// testClient configures a client authenticated against srv whose requests
+// to the overridden endpoints are redirected as well.
+func testClient(t *testing.T, srv *httptest.Server, endpoints map[string]string) Client {
+	key, _ := testServiceAccountKey(t, srv.URL+"/token")
+	defer os.RemoveAll(filepath.Dir(key))
+
+	meta, err := Config{
+		ServiceAccountKey: key,
+		Endpoints:         endpoints,
+	}.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return meta.(Client)
+}
+
+// testAPIClient starts a server where handler answers the requests of a
+// client to the endpoints, under the same paths.
+func testAPIClient(t *testing.T, handler http.Handler, endpoints ...string) (Client, func()) {
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	srv := testServer(mux)
+
+	overrides := make(map[string]string)
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		overrides[endpoint] = srv.URL + u.Path
+	}
+	return testClient(t, srv, overrides), srv.Close
+}
+
+// testResourceConfig returns the configuration of a resource, to plan it
+// with Resource.Diff.
+func testResourceConfig(t *testing.T, raw map[string]interface{}) *terraform.ResourceConfig {
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return terraform.NewResourceConfig(c)
+}
+
+// testUnitProviders returns providers whose Identity Toolkit requests are
+// served by srv, so resource.UnitTest lifecycles run without a live
+// project. Callers should call the returned func once done.
+func testUnitProviders(t *testing.T, srv *authtest.Server) (map[string]terraform.ResourceProvider, *schema.Provider, func()) {
+	key, _ := testServiceAccountKey(t, srv.TokenURI())
+
+	p := Provider().(*schema.Provider)
+	p.Schema["service_account_key"].DefaultFunc = func() (interface{}, error) {
+		return key, nil
+	}
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return Config{
+			ServiceAccountKey: d.Get("service_account_key").(string),
+			Project:           d.Get("project").(string),
+			Endpoints:         srv.Endpoints(),
+		}.Client()
+	}
+	return map[string]terraform.ResourceProvider{"firebase": p}, p, func() { os.RemoveAll(filepath.Dir(key)) }
+}
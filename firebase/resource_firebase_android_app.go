@@ -0,0 +1,216 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type androidApp struct {
+	DisplayName string `json:"displayName,omitempty"`
+	PackageName string `json:"packageName,omitempty"`
+}
+
+type shaCertificate struct {
+	Name     string `json:"name,omitempty"`
+	ShaHash  string `json:"shaHash"`
+	CertType string `json:"certType"`
+}
+
+// androidAppFields maps the schema to the Android app fields for update
+// masks.
+var androidAppFields = map[string]string{
+	"display_name": "displayName",
+}
+
+// androidAppCertTypes maps the certificate fingerprint sets to their types.
+var androidAppCertTypes = map[string]string{
+	"sha1_hashes":   "SHA_1",
+	"sha256_hashes": "SHA_256",
+}
+
+func resourceFirebaseAndroidApp() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseAndroidAppCreate,
+		Read:   resourceFirebaseAndroidAppRead,
+		Update: resourceFirebaseAndroidAppUpdate,
+		Delete: resourceFirebaseAndroidAppDelete,
+		Importer: &schema.ResourceImporter{
+			State: firebaseAndroidAppType.importState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: firebaseAppSchema(map[string]*schema.Schema{
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"package_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAndroidPackageName,
+			},
+			"sha1_hashes": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCertificateHash(20),
+				},
+				Set: hashCertificateHash,
+			},
+			"sha256_hashes": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCertificateHash(32),
+				},
+				Set: hashCertificateHash,
+			},
+		}),
+	}
+}
+
+func resourceFirebaseAndroidAppCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseAndroidAppType.create(d, meta, expandAndroidApp(d)); err != nil {
+		return err
+	}
+	if err := updateAndroidAppCertificates(d, meta); err != nil {
+		return err
+	}
+	return resourceFirebaseAndroidAppRead(d, meta)
+}
+
+func resourceFirebaseAndroidAppRead(d *schema.ResourceData, meta interface{}) error {
+	var app androidApp
+	if ok, err := firebaseAndroidAppType.read(d, meta, &app); !ok {
+		return err
+	}
+
+	d.Set("display_name", app.DisplayName)
+	d.Set("package_name", app.PackageName)
+
+	certs, err := listAndroidAppCertificates(d, meta)
+	if err != nil {
+		return err
+	}
+	for k, certType := range androidAppCertTypes {
+		hashes := schema.NewSet(hashCertificateHash, nil)
+		for _, cert := range certs {
+			if cert.CertType == certType {
+				hashes.Add(normalizeCertificateHash(cert.ShaHash))
+			}
+		}
+		d.Set(k, hashes)
+	}
+
+	return nil
+}
+
+func resourceFirebaseAndroidAppUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseAndroidAppType.update(d, meta, expandAndroidApp(d), androidAppFields); err != nil {
+		return err
+	}
+	if err := updateAndroidAppCertificates(d, meta); err != nil {
+		return err
+	}
+	return resourceFirebaseAndroidAppRead(d, meta)
+}
+
+func resourceFirebaseAndroidAppDelete(d *schema.ResourceData, meta interface{}) error {
+	return firebaseAndroidAppType.remove(d, meta)
+}
+
+func listAndroidAppCertificates(d *schema.ResourceData, meta interface{}) ([]shaCertificate, error) {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Certificates []shaCertificate `json:"certificates"`
+	}
+	url := firebaseAndroidAppType.url(client.ProjectID, d.Id()) + "/sha"
+	if err := sendRequest(context.Background(), client.HTTP, "GET", url, nil, &res); err != nil {
+		return nil, fmt.Errorf("Error reading certificates of Android app (%s): %s", d.Id(), err)
+	}
+	return res.Certificates, nil
+}
+
+// updateAndroidAppCertificates adds and deletes certificates so the app's
+// fingerprints match the configuration.
+func updateAndroidAppCertificates(d *schema.ResourceData, meta interface{}) error {
+	if !d.HasChange("sha1_hashes") && !d.HasChange("sha256_hashes") {
+		return nil
+	}
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	certs, err := listAndroidAppCertificates(d, meta)
+	if err != nil {
+		return err
+	}
+
+	for k, certType := range androidAppCertTypes {
+		o, n := d.GetChange(k)
+		remove := o.(*schema.Set).Difference(n.(*schema.Set))
+		add := n.(*schema.Set).Difference(o.(*schema.Set))
+
+		for _, cert := range certs {
+			if cert.CertType != certType || !remove.Contains(normalizeCertificateHash(cert.ShaHash)) {
+				continue
+			}
+			log.Printf("[INFO] Deleting certificate of Android app %s: %s", d.Id(), cert.ShaHash)
+
+			url := fmt.Sprintf("%s/%s", firebaseManagementEndpoint, cert.Name)
+			err := sendRequest(context.Background(), client.HTTP, "DELETE", url, nil, nil)
+			if err != nil && !isNotFound(err) {
+				return fmt.Errorf("Error deleting certificate of Android app (%s): %s", d.Id(), err)
+			}
+		}
+
+		for _, hash := range add.List() {
+			cert := &shaCertificate{
+				ShaHash:  normalizeCertificateHash(hash),
+				CertType: certType,
+			}
+			log.Printf("[INFO] Adding certificate to Android app %s: %s", d.Id(), cert.ShaHash)
+
+			url := firebaseAndroidAppType.url(client.ProjectID, d.Id()) + "/sha"
+			if err := sendRequest(context.Background(), client.HTTP, "POST", url, cert, nil); err != nil {
+				return fmt.Errorf("Error adding certificate to Android app (%s): %s", d.Id(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func expandAndroidApp(d *schema.ResourceData) *androidApp {
+	return &androidApp{
+		DisplayName: d.Get("display_name").(string),
+		PackageName: d.Get("package_name").(string),
+	}
+}
+
+// normalizeCertificateHash returns the lowercase hex form the Management API
+// uses for a fingerprint, which keytool prints uppercase and colon separated.
+func normalizeCertificateHash(v interface{}) string {
+	return strings.ToLower(strings.Replace(v.(string), ":", "", -1))
+}
+
+func hashCertificateHash(v interface{}) int {
+	return hashcode.String(normalizeCertificateHash(v))
+}
@@ -0,0 +1,100 @@
+package firebase
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type appleApp struct {
+	DisplayName string `json:"displayName,omitempty"`
+	BundleID    string `json:"bundleId,omitempty"`
+	AppStoreID  string `json:"appStoreId,omitempty"`
+	TeamID      string `json:"teamId,omitempty"`
+}
+
+// appleAppFields maps the schema to the iOS app fields for update masks.
+var appleAppFields = map[string]string{
+	"display_name": "displayName",
+	"app_store_id": "appStoreId",
+	"team_id":      "teamId",
+}
+
+func resourceFirebaseAppleApp() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseAppleAppCreate,
+		Read:   resourceFirebaseAppleAppRead,
+		Update: resourceFirebaseAppleAppUpdate,
+		Delete: resourceFirebaseAppleAppDelete,
+		Importer: &schema.ResourceImporter{
+			State: firebaseAppleAppType.importState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: firebaseAppSchema(map[string]*schema.Schema{
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"bundle_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"app_store_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAppStoreID,
+			},
+			"team_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		}),
+	}
+}
+
+func resourceFirebaseAppleAppCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseAppleAppType.create(d, meta, expandAppleApp(d)); err != nil {
+		return err
+	}
+	return resourceFirebaseAppleAppRead(d, meta)
+}
+
+func resourceFirebaseAppleAppRead(d *schema.ResourceData, meta interface{}) error {
+	var app appleApp
+	if ok, err := firebaseAppleAppType.read(d, meta, &app); !ok {
+		return err
+	}
+
+	d.Set("display_name", app.DisplayName)
+	d.Set("bundle_id", app.BundleID)
+	d.Set("app_store_id", app.AppStoreID)
+	d.Set("team_id", app.TeamID)
+
+	return nil
+}
+
+func resourceFirebaseAppleAppUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseAppleAppType.update(d, meta, expandAppleApp(d), appleAppFields); err != nil {
+		return err
+	}
+	return resourceFirebaseAppleAppRead(d, meta)
+}
+
+func resourceFirebaseAppleAppDelete(d *schema.ResourceData, meta interface{}) error {
+	return firebaseAppleAppType.remove(d, meta)
+}
+
+func expandAppleApp(d *schema.ResourceData) *appleApp {
+	return &appleApp{
+		DisplayName: d.Get("display_name").(string),
+		BundleID:    d.Get("bundle_id").(string),
+		AppStoreID:  d.Get("app_store_id").(string),
+		TeamID:      d.Get("team_id").(string),
+	}
+}
@@ -0,0 +1,200 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const identityPlatformEndpoint = "https://identitytoolkit.googleapis.com/v2"
+
+type authTenant struct {
+	Name                  string `json:"name,omitempty"`
+	DisplayName           string `json:"displayName"`
+	AllowPasswordSignup   bool   `json:"allowPasswordSignup"`
+	EnableEmailLinkSignin bool   `json:"enableEmailLinkSignin"`
+	EnableAnonymousUser   bool   `json:"enableAnonymousUser"`
+	DisableAuth           bool   `json:"disableAuth"`
+}
+
+// authTenantFields maps the schema to the tenant fields for update masks.
+var authTenantFields = map[string]string{
+	"display_name":             "displayName",
+	"allow_password_signup":    "allowPasswordSignup",
+	"enable_email_link_signin": "enableEmailLinkSignin",
+	"enable_anonymous_user":    "enableAnonymousUser",
+	"disable_auth":             "disableAuth",
+}
+
+func resourceFirebaseAuthTenant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseAuthTenantCreate,
+		Read:   resourceFirebaseAuthTenantRead,
+		Update: resourceFirebaseAuthTenantUpdate,
+		Delete: resourceFirebaseAuthTenantDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseAuthTenantImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"display_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTenantDisplayName,
+			},
+			"allow_password_signup": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"enable_email_link_signin": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"enable_anonymous_user": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"disable_auth": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"tenant_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseAuthTenantCreate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Creating tenant: %s", d.Get("display_name").(string))
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/projects/%s/tenants", identityPlatformEndpoint, client.ProjectID)
+
+	var tenant authTenant
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, expandAuthTenant(d), &tenant)
+	if err != nil {
+		return fmt.Errorf("Error creating tenant (%s): %s", d.Get("display_name").(string), err)
+	}
+
+	d.SetId(tenant.Name[strings.LastIndex(tenant.Name, "/")+1:])
+	log.Printf("[INFO] Tenant ID: %s", d.Id())
+
+	return resourceFirebaseAuthTenantRead(d, meta)
+}
+
+func resourceFirebaseAuthTenantRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading tenant: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/projects/%s/tenants/%s", identityPlatformEndpoint, client.ProjectID, d.Id())
+
+	var tenant authTenant
+	err = sendRequest(context.Background(), client.HTTP, "GET", url, nil, &tenant)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Tenant (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading tenant (%s): %s", d.Id(), err)
+	}
+
+	d.Set("tenant_id", d.Id())
+	d.Set("name", tenant.Name)
+	d.Set("display_name", tenant.DisplayName)
+	d.Set("allow_password_signup", tenant.AllowPasswordSignup)
+	d.Set("enable_email_link_signin", tenant.EnableEmailLinkSignin)
+	d.Set("enable_anonymous_user", tenant.EnableAnonymousUser)
+	d.Set("disable_auth", tenant.DisableAuth)
+
+	return nil
+}
+
+func resourceFirebaseAuthTenantUpdate(d *schema.ResourceData, meta interface{}) error {
+	var mask []string
+	for k, field := range authTenantFields {
+		if d.HasChange(k) {
+			mask = append(mask, field)
+		}
+	}
+
+	if len(mask) > 0 {
+		log.Printf("[INFO] Updating tenant %s: %v", d.Id(), mask)
+
+		client, err := projectClient(d, meta)
+		if err != nil {
+			return err
+		}
+		url := fmt.Sprintf("%s/projects/%s/tenants/%s?updateMask=%s",
+			identityPlatformEndpoint, client.ProjectID, d.Id(), strings.Join(mask, ","))
+
+		err = sendRequest(context.Background(), client.HTTP, "PATCH", url, expandAuthTenant(d), nil)
+		if err != nil {
+			return fmt.Errorf("Error updating tenant (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceFirebaseAuthTenantRead(d, meta)
+}
+
+func resourceFirebaseAuthTenantDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting tenant: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/projects/%s/tenants/%s", identityPlatformEndpoint, client.ProjectID, d.Id())
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", url, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting tenant (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseAuthTenantImportState accepts a tenant ID or the tenant's
+// name, projects/<project>/tenants/<tenant_id>.
+func resourceFirebaseAuthTenantImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "tenants":
+		d.Set("project", parts[1])
+		d.SetId(parts[3])
+	default:
+		return nil, fmt.Errorf("Import ID %q should be a tenant ID or projects/<project>/tenants/<tenant_id>", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandAuthTenant(d *schema.ResourceData) *authTenant {
+	return &authTenant{
+		DisplayName:           d.Get("display_name").(string),
+		AllowPasswordSignup:   d.Get("allow_password_signup").(bool),
+		EnableEmailLinkSignin: d.Get("enable_email_link_signin").(bool),
+		EnableAnonymousUser:   d.Get("enable_anonymous_user").(bool),
+		DisableAuth:           d.Get("disable_auth").(bool),
+	}
+}
@@ -0,0 +1,268 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testIdentityPlatformServer fakes the Identity Platform v2 tenant API and
+// the v1 accounts API of its tenants.
+type testIdentityPlatformServer struct {
+	sync.Mutex
+	tenants map[string]map[string]interface{}
+	users   map[string]map[string]map[string]interface{}
+	masks   []string
+}
+
+func (s *testIdentityPlatformServer) handleTenants(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	prefix := fmt.Sprintf("/v2/projects/%s/tenants", testProjectID)
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch {
+	case r.Method == "POST" && id == "":
+		id = fmt.Sprintf("%s-%d", strings.ToLower(body["displayName"].(string)), len(s.tenants))
+		body["name"] = fmt.Sprintf("projects/%s/tenants/%s", testProjectID, id)
+		s.tenants[id] = body
+		s.users[id] = make(map[string]map[string]interface{})
+		json.NewEncoder(w).Encode(body)
+		return
+	case s.tenants[id] == nil:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"TENANT_NOT_FOUND"}}`)
+		return
+	case r.Method == "GET":
+	case r.Method == "PATCH":
+		mask := r.URL.Query().Get("updateMask")
+		s.masks = append(s.masks, mask)
+		for _, field := range strings.Split(mask, ",") {
+			s.tenants[id][field] = body[field]
+		}
+	case r.Method == "DELETE":
+		delete(s.tenants, id)
+		fmt.Fprint(w, `{}`)
+		return
+	}
+	json.NewEncoder(w).Encode(s.tenants[id])
+}
+
+func (s *testIdentityPlatformServer) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	// /v1/projects/<project>/tenants/<tenant>/<method>
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 7 || s.users[parts[5]] == nil {
+		http.NotFound(w, r)
+		return
+	}
+	users := s.users[parts[5]]
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch parts[6] {
+	case "accounts":
+		uid := body["localId"].(string)
+		users[uid] = body
+		fmt.Fprintf(w, `{"localId":%q}`, uid)
+	case "accounts:lookup":
+		var found []map[string]interface{}
+		for _, u := range users {
+			for _, k := range []string{"localId", "email"} {
+				values, _ := body[k].([]interface{})
+				if len(values) == 1 && u[k] == values[0] {
+					found = append(found, u)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"users": found})
+	case "accounts:update":
+		uid := body["localId"].(string)
+		for k, v := range body {
+			users[uid][k] = v
+		}
+		fmt.Fprintf(w, `{"localId":%q}`, uid)
+	case "accounts:delete":
+		delete(users, body["localId"].(string))
+		fmt.Fprint(w, `{}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestResourceFirebaseAuthTenant(t *testing.T) {
+	fake := &testIdentityPlatformServer{
+		tenants: make(map[string]map[string]interface{}),
+		users:   make(map[string]map[string]map[string]interface{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", fake.handleTenants)
+	mux.HandleFunc("/v1/", fake.handleAccounts)
+	mux.HandleFunc("/relyingparty/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected project scoped request: %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		"https://identitytoolkit.googleapis.com": srv.URL,
+		identityToolkitEndpoint:                  srv.URL + "/relyingparty",
+	})
+
+	r := resourceFirebaseAuthTenant()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"display_name":          "acme",
+		"allow_password_signup": true,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	tenantID := d.Id()
+	if tenantID != "acme-0" || d.Get("tenant_id") != tenantID {
+		t.Fatalf("incorrect tenant ID: %q", tenantID)
+	}
+	if d.Get("name") != fmt.Sprintf("projects/%s/tenants/acme-0", testProjectID) {
+		t.Fatalf("incorrect name: %q", d.Get("name"))
+	}
+	if !d.Get("allow_password_signup").(bool) || d.Get("enable_email_link_signin").(bool) {
+		t.Fatalf("incorrect sign-in methods")
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"display_name":             "acme",
+		"allow_password_signup":    true,
+		"enable_email_link_signin": true,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(d.State(), terraform.NewResourceConfig(c), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.masks) != "[enableEmailLinkSignin]" {
+		t.Fatalf("unexpected update masks: %v", fake.masks)
+	}
+	if state.Attributes["enable_email_link_signin"] != "true" {
+		t.Fatalf("enable_email_link_signin was not updated")
+	}
+
+	// Users of the tenant are managed through the tenant's accounts API
+	u := resourceFirebaseUser()
+	ud := schema.TestResourceDataRaw(t, u.Schema, map[string]interface{}{
+		"uid":          testUser.UserInfo.UID,
+		"tenant_id":    tenantID,
+		"email":        testUser.UserInfo.Email,
+		"display_name": testUser.UserInfo.DisplayName,
+		"phone_number": testUser.UserInfo.PhoneNumber,
+		"photo_url":    testUser.UserInfo.PhotoURL,
+		"password":     "password123",
+	})
+	if err := u.Create(ud, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.users[tenantID][testUser.UserInfo.UID] == nil {
+		t.Fatalf("user was not created in tenant %s", tenantID)
+	}
+	first, _ := meta.tenantAuth(tenantID)
+	if second, _ := meta.tenantAuth(tenantID); first == nil || first != second {
+		t.Fatalf("tenant auth client was not cached")
+	}
+	if ud.Get("email") != testUser.UserInfo.Email {
+		t.Fatalf("incorrect email: %q", ud.Get("email"))
+	}
+
+	for _, id := range []string{
+		fmt.Sprintf("tenants/%s/email:%s", tenantID, testUser.UserInfo.Email),
+		fmt.Sprintf("tenants/%s/%s", tenantID, testUser.UserInfo.UID),
+	} {
+		states, err := u.Importer.State(u.Data(&terraform.InstanceState{ID: id}), meta)
+		if err != nil {
+			t.Fatalf("%s: err: %s", id, err)
+		}
+		if states[0].Get("tenant_id") != tenantID || states[0].Id() != testUser.UserInfo.UID {
+			t.Fatalf("%s: incorrect import: %s %s", id, states[0].Get("tenant_id"), states[0].Id())
+		}
+	}
+	if _, err := u.Importer.State(u.Data(&terraform.InstanceState{ID: "tenants//uid"}), meta); err == nil {
+		t.Fatalf("expected error for missing tenant ID")
+	}
+
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("deleted tenant was kept in state")
+	}
+}
+
+type testRoundTripper func(*http.Request) (*http.Response, error)
+
+func (f testRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAccountsTransport(t *testing.T) {
+	var sent []string
+	base := testRoundTripper(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.URL.String())
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	project := &accountsTransport{projectID: testProjectID, base: base}
+	tenant := &accountsTransport{projectID: testProjectID, tenantID: "acme-0", base: base}
+	for _, tr := range []*accountsTransport{project, tenant} {
+		req, _ := http.NewRequest("POST", identityToolkitEndpoint+"/getAccountInfo?alt=json", nil)
+		if _, err := tr.RoundTrip(req); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Methods without an accounts counterpart only reach the project's users
+	req, _ := http.NewRequest("POST", identityToolkitEndpoint+"/downloadAccount", nil)
+	if _, err := project.RoundTrip(req); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := tenant.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "downloadAccount is not supported for tenant acme-0") {
+		t.Fatalf("expected unsupported method error, got: %v", err)
+	}
+
+	expected := []string{
+		fmt.Sprintf("%s/projects/%s/accounts:lookup?alt=json", identityToolkitV1Endpoint, testProjectID),
+		fmt.Sprintf("%s/projects/%s/tenants/acme-0/accounts:lookup?alt=json", identityToolkitV1Endpoint, testProjectID),
+		identityToolkitEndpoint + "/downloadAccount",
+	}
+	if fmt.Sprint(sent) != fmt.Sprint(expected) {
+		t.Fatalf("incorrect requests: %v", sent)
+	}
+}
@@ -0,0 +1,278 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type firestoreField struct {
+	Name        string                `json:"name,omitempty"`
+	IndexConfig *firestoreIndexConfig `json:"indexConfig,omitempty"`
+	TTLConfig   *firestoreTTLConfig   `json:"ttlConfig,omitempty"`
+}
+
+type firestoreIndexConfig struct {
+	Indexes            []firestoreIndex `json:"indexes"`
+	UsesAncestorConfig bool             `json:"usesAncestorConfig,omitempty"`
+}
+
+type firestoreTTLConfig struct {
+	State string `json:"state,omitempty"`
+}
+
+func resourceFirebaseFirestoreFieldOverride() *schema.Resource {
+	index := firestoreIndexFieldSchema(false)
+	index["query_scope"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "COLLECTION",
+		ValidateFunc: validation.StringInSlice([]string{"COLLECTION", "COLLECTION_GROUP"}, false),
+	}
+
+	return &schema.Resource{
+		Create: resourceFirebaseFirestoreFieldOverrideCreate,
+		Read:   resourceFirebaseFirestoreFieldOverrideRead,
+		Update: resourceFirebaseFirestoreFieldOverrideUpdate,
+		Delete: resourceFirebaseFirestoreFieldOverrideDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseFirestoreFieldOverrideImportState,
+		},
+
+		CustomizeDiff: resourceFirebaseFirestoreFieldOverrideCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "(default)",
+			},
+			"collection": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"field": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// The single field indexes kept for the field, without any the
+			// field is exempted from indexing
+			"index": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"use_default_indexes"},
+				Elem: &schema.Resource{
+					Schema: index,
+				},
+			},
+			// Keeps the database's automatic indexes, to only manage the
+			// TTL policy of the field
+			"use_default_indexes": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// Documents are deleted once the timestamp in the field expires
+			"ttl": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ttl_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseFirestoreFieldOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("projects/%s/databases/%s/collectionGroups/%s/fields/%s",
+		client.ProjectID, d.Get("database").(string), d.Get("collection").(string), d.Get("field").(string))
+	log.Printf("[INFO] Creating Firestore field override: %s", name)
+
+	if err := updateFirestoreField(d, client, name, []string{"indexConfig", "ttlConfig"}, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fmt.Errorf("Error creating Firestore field override (%s): %s", name, err)
+	}
+
+	d.SetId(name)
+
+	return resourceFirebaseFirestoreFieldOverrideRead(d, meta)
+}
+
+func resourceFirebaseFirestoreFieldOverrideRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Firestore field override: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var field firestoreField
+	err = sendRequest(context.Background(), client.HTTP, "GET", firestoreEndpoint+"/"+d.Id(), nil, &field)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Firestore field override (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Firestore field override (%s): %s", d.Id(), err)
+	}
+
+	config := field.IndexConfig
+	if config == nil {
+		config = &firestoreIndexConfig{UsesAncestorConfig: true}
+	}
+	indexes := make([]map[string]interface{}, 0)
+	if !config.UsesAncestorConfig {
+		for _, index := range config.Indexes {
+			for _, f := range index.Fields {
+				indexes = append(indexes, map[string]interface{}{
+					"query_scope":  index.QueryScope,
+					"order":        f.Order,
+					"array_config": f.ArrayConfig,
+				})
+			}
+		}
+	}
+	if err := d.Set("index", indexes); err != nil {
+		return fmt.Errorf("Error setting index: %s", err)
+	}
+	d.Set("use_default_indexes", config.UsesAncestorConfig)
+
+	ttlState := ""
+	if field.TTLConfig != nil {
+		ttlState = field.TTLConfig.State
+	}
+	d.Set("ttl", field.TTLConfig != nil)
+	d.Set("ttl_state", ttlState)
+	d.Set("name", field.Name)
+
+	return nil
+}
+
+func resourceFirebaseFirestoreFieldOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var mask []string
+	if d.HasChange("index") || d.HasChange("use_default_indexes") {
+		mask = append(mask, "indexConfig")
+	}
+	if d.HasChange("ttl") {
+		mask = append(mask, "ttlConfig")
+	}
+	if len(mask) > 0 {
+		log.Printf("[INFO] Updating Firestore field override: %s", d.Id())
+
+		if err := updateFirestoreField(d, client, d.Id(), mask, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return fmt.Errorf("Error updating Firestore field override (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceFirebaseFirestoreFieldOverrideRead(d, meta)
+}
+
+func resourceFirebaseFirestoreFieldOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Firestore field override: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Fields without an index config use the database's automatic indexes,
+	// and those without a TTL config have no TTL policy
+	var op operation
+	url := firestoreEndpoint + "/" + d.Id() + "?updateMask=indexConfig,ttlConfig"
+	err = sendRequest(context.Background(), client.HTTP, "PATCH", url, &firestoreField{Name: d.Id()}, &op)
+	if err == nil {
+		err = waitForOperationWithProgress(client.HTTP, firestoreEndpoint, &op, d.Timeout(schema.TimeoutDelete), nil,
+			firestoreOperationProgress(fmt.Sprintf("default indexes of Firestore field (%s)", d.Id())))
+	}
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting Firestore field override (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseFirestoreFieldOverrideImportState accepts the field's name,
+// projects/<project>/databases/<database>/collectionGroups/<collection>/fields/<field>.
+func resourceFirebaseFirestoreFieldOverrideImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 8)
+	if len(parts) != 8 || parts[0] != "projects" || parts[2] != "databases" || parts[4] != "collectionGroups" || parts[6] != "fields" {
+		return nil, fmt.Errorf("Import ID %q should be projects/<project>/databases/<database>/collectionGroups/<collection>/fields/<field>", d.Id())
+	}
+	d.Set("project", parts[1])
+	d.Set("database", parts[3])
+	d.Set("collection", parts[5])
+	d.Set("field", parts[7])
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceFirebaseFirestoreFieldOverrideCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("index") {
+		return nil
+	}
+	for _, index := range d.Get("index").(*schema.Set).List() {
+		if err := firestoreIndexFieldError(index.(map[string]interface{})); err != nil {
+			return fmt.Errorf("index: %s", err)
+		}
+	}
+	return nil
+}
+
+// updateFirestoreField patches the masked configs of the field and waits
+// for its indexes to be built.
+func updateFirestoreField(d *schema.ResourceData, client Client, name string, mask []string, timeout time.Duration) error {
+	field := &firestoreField{Name: name}
+	if !d.Get("use_default_indexes").(bool) {
+		field.IndexConfig = &firestoreIndexConfig{Indexes: make([]firestoreIndex, 0)}
+		for _, v := range d.Get("index").(*schema.Set).List() {
+			index := v.(map[string]interface{})
+			field.IndexConfig.Indexes = append(field.IndexConfig.Indexes, firestoreIndex{
+				QueryScope: index["query_scope"].(string),
+				Fields: []firestoreIndexField{{
+					FieldPath:   d.Get("field").(string),
+					Order:       index["order"].(string),
+					ArrayConfig: index["array_config"].(string),
+				}},
+			})
+		}
+	}
+	if d.Get("ttl").(bool) {
+		field.TTLConfig = &firestoreTTLConfig{}
+	}
+
+	var op operation
+	url := fmt.Sprintf("%s/%s?updateMask=%s", firestoreEndpoint, name, strings.Join(mask, ","))
+	if err := sendRequest(context.Background(), client.HTTP, "PATCH", url, field, &op); err != nil {
+		return err
+	}
+	return waitForOperationWithProgress(client.HTTP, firestoreEndpoint, &op, timeout, nil,
+		firestoreOperationProgress(fmt.Sprintf("indexes of Firestore field (%s)", name)))
+}
@@ -0,0 +1,334 @@
+package firebase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const firestoreEndpoint = "https://firestore.googleapis.com/v1"
+
+type firestoreIndex struct {
+	Name       string                `json:"name,omitempty"`
+	QueryScope string                `json:"queryScope,omitempty"`
+	Fields     []firestoreIndexField `json:"fields"`
+	State      string                `json:"state,omitempty"`
+}
+
+type firestoreIndexField struct {
+	FieldPath   string `json:"fieldPath"`
+	Order       string `json:"order,omitempty"`
+	ArrayConfig string `json:"arrayConfig,omitempty"`
+}
+
+// firestoreOperationMetadata is the metadata of index and field operations.
+type firestoreOperationMetadata struct {
+	Index             string             `json:"index"`
+	Field             string             `json:"field"`
+	State             string             `json:"state"`
+	ProgressDocuments *firestoreProgress `json:"progressDocuments"`
+	ProgressBytes     *firestoreProgress `json:"progressBytes"`
+}
+
+type firestoreProgress struct {
+	EstimatedWork int64 `json:"estimatedWork,string"`
+	CompletedWork int64 `json:"completedWork,string"`
+}
+
+func resourceFirebaseFirestoreIndex() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseFirestoreIndexCreate,
+		Read:   resourceFirebaseFirestoreIndexRead,
+		Delete: resourceFirebaseFirestoreIndexDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseFirestoreIndexImportState,
+		},
+
+		CustomizeDiff: resourceFirebaseFirestoreIndexCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"database": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "(default)",
+			},
+			"collection": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"query_scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "COLLECTION",
+				ValidateFunc: validation.StringInSlice([]string{"COLLECTION", "COLLECTION_GROUP"}, false),
+			},
+			// Single field indexes are configured with field overrides
+			"field": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 2,
+				Elem: &schema.Resource{
+					Schema: firestoreIndexFieldSchema(true),
+				},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// firestoreIndexFieldSchema holds how a field is indexed, by order or as an
+// array, along with its path in composite indexes.
+func firestoreIndexFieldSchema(composite bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"order": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     composite,
+			ValidateFunc: validation.StringInSlice([]string{"ASCENDING", "DESCENDING"}, false),
+		},
+		"array_config": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     composite,
+			ValidateFunc: validation.StringInSlice([]string{"CONTAINS"}, false),
+		},
+	}
+	if composite {
+		s["field_path"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		}
+	}
+	return s
+}
+
+func firestoreDatabaseURL(project, database string) string {
+	return fmt.Sprintf("%s/projects/%s/databases/%s", firestoreEndpoint, project, database)
+}
+
+func firestoreCollectionGroupURL(project, database, collection string) string {
+	return fmt.Sprintf("%s/collectionGroups/%s", firestoreDatabaseURL(project, database), collection)
+}
+
+func resourceFirebaseFirestoreIndexCreate(d *schema.ResourceData, meta interface{}) error {
+	collection := d.Get("collection").(string)
+	log.Printf("[INFO] Creating Firestore index of collection: %s", collection)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := firestoreCollectionGroupURL(client.ProjectID, d.Get("database").(string), collection) + "/indexes"
+
+	index := &firestoreIndex{
+		QueryScope: d.Get("query_scope").(string),
+		Fields:     expandFirestoreIndexFields(d.Get("field").([]interface{})),
+	}
+	var op operation
+	if err := sendRequest(context.Background(), client.HTTP, "POST", url, index, &op); err != nil {
+		return fmt.Errorf("Error creating Firestore index of collection (%s): %s", collection, err)
+	}
+
+	var metadata firestoreOperationMetadata
+	if err := json.Unmarshal(op.Metadata, &metadata); err != nil || metadata.Index == "" {
+		return fmt.Errorf("Error creating Firestore index of collection (%s): operation %s has no index", collection, op.Name)
+	}
+	d.SetId(metadata.Index)
+	log.Printf("[INFO] Firestore index: %s", d.Id())
+
+	err = waitForOperationWithProgress(client.HTTP, firestoreEndpoint, &op, d.Timeout(schema.TimeoutCreate), nil,
+		firestoreOperationProgress(fmt.Sprintf("Firestore index (%s)", d.Id())))
+	if err != nil {
+		return fmt.Errorf("Error building Firestore index (%s): %s", d.Id(), err)
+	}
+
+	if err := waitForFirestoreIndex(client, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	return resourceFirebaseFirestoreIndexRead(d, meta)
+}
+
+func resourceFirebaseFirestoreIndexRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Firestore index: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var index firestoreIndex
+	err = sendRequest(context.Background(), client.HTTP, "GET", firestoreEndpoint+"/"+d.Id(), nil, &index)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Firestore index (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Firestore index (%s): %s", d.Id(), err)
+	}
+
+	// Firestore appends the document name to the fields of indexes which
+	// do not order by it
+	fields := index.Fields
+	configured := d.Get("field").([]interface{})
+	if n := len(fields); n > 0 && fields[n-1].FieldPath == "__name__" && len(configured) < n {
+		fields = fields[:n-1]
+	}
+	if err := d.Set("field", flattenFirestoreIndexFields(fields)); err != nil {
+		return fmt.Errorf("Error setting field: %s", err)
+	}
+	d.Set("query_scope", index.QueryScope)
+	d.Set("name", index.Name)
+	d.Set("state", index.State)
+
+	return nil
+}
+
+func resourceFirebaseFirestoreIndexDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Firestore index: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", firestoreEndpoint+"/"+d.Id(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting Firestore index (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseFirestoreIndexImportState accepts the index's name,
+// projects/<project>/databases/<database>/collectionGroups/<collection>/indexes/<index_id>.
+func resourceFirebaseFirestoreIndexImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 8)
+	if len(parts) != 8 || parts[0] != "projects" || parts[2] != "databases" || parts[4] != "collectionGroups" || parts[6] != "indexes" {
+		return nil, fmt.Errorf("Import ID %q should be projects/<project>/databases/<database>/collectionGroups/<collection>/indexes/<index_id>", d.Id())
+	}
+	d.Set("project", parts[1])
+	d.Set("database", parts[3])
+	d.Set("collection", parts[5])
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceFirebaseFirestoreIndexCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("field") {
+		return nil
+	}
+	for i, f := range d.Get("field").([]interface{}) {
+		if err := firestoreIndexFieldError(f.(map[string]interface{})); err != nil {
+			return fmt.Errorf("field.%d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// firestoreIndexFieldError checks that a field is indexed either by order
+// or as an array.
+func firestoreIndexFieldError(f map[string]interface{}) error {
+	order, arrayConfig := f["order"].(string), f["array_config"].(string)
+	if (order == "") == (arrayConfig == "") {
+		return fmt.Errorf("exactly one of order or array_config should be set")
+	}
+	return nil
+}
+
+// waitForFirestoreIndex waits until the index serves queries.
+func waitForFirestoreIndex(client Client, name string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for Firestore index (%s) to be ready", name)
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"CREATING"},
+		Target:  []string{"READY"},
+		Refresh: func() (interface{}, string, error) {
+			var index firestoreIndex
+			if err := sendRequest(context.Background(), client.HTTP, "GET", firestoreEndpoint+"/"+name, nil, &index); err != nil {
+				return nil, "", err
+			}
+			if index.State == "NEEDS_REPAIR" {
+				return nil, "", fmt.Errorf("index needs repair, delete and recreate it")
+			}
+			return &index, index.State, nil
+		},
+		Timeout:    timeout,
+		Delay:      pollDelay,
+		MinTimeout: pollMinTimeout,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for Firestore index (%s) to be ready: %s", name, err)
+	}
+	return nil
+}
+
+// firestoreOperationProgress returns an operation progress func which logs
+// the documents and bytes of what is indexed, whenever they change.
+func firestoreOperationProgress(what string) func(*operation) {
+	last := ""
+	return func(op *operation) {
+		var metadata firestoreOperationMetadata
+		if err := json.Unmarshal(op.Metadata, &metadata); err != nil || metadata.ProgressDocuments == nil {
+			return
+		}
+		progress := fmt.Sprintf("%d of %d documents", metadata.ProgressDocuments.CompletedWork, metadata.ProgressDocuments.EstimatedWork)
+		if p := metadata.ProgressBytes; p != nil {
+			progress += fmt.Sprintf(", %d of %d bytes", p.CompletedWork, p.EstimatedWork)
+		}
+		if progress != last {
+			log.Printf("[INFO] Building %s: %s", what, progress)
+			last = progress
+		}
+	}
+}
+
+func expandFirestoreIndexFields(v []interface{}) []firestoreIndexField {
+	fields := make([]firestoreIndexField, 0, len(v))
+	for _, f := range v {
+		field := f.(map[string]interface{})
+		fields = append(fields, firestoreIndexField{
+			FieldPath:   field["field_path"].(string),
+			Order:       field["order"].(string),
+			ArrayConfig: field["array_config"].(string),
+		})
+	}
+	return fields
+}
+
+func flattenFirestoreIndexFields(fields []firestoreIndexField) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(fields))
+	for _, f := range fields {
+		result = append(result, map[string]interface{}{
+			"field_path":   f.FieldPath,
+			"order":        f.Order,
+			"array_config": f.ArrayConfig,
+		})
+	}
+	return result
+}
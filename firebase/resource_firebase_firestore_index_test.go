@@ -0,0 +1,226 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testFirestoreServer fakes the Firestore Admin API. Index builds report
+// their progress and are done on their second poll.
+type testFirestoreServer struct {
+	sync.Mutex
+	indexes    map[string]*firestoreIndex
+	fields     map[string]map[string]interface{}
+	operations map[string]int
+	masks      []string
+}
+
+func (s *testFirestoreServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1/")
+	database := fmt.Sprintf("projects/%s/databases/(default)", testProjectID)
+	fail := func(code int, message string) {
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+	}
+
+	switch {
+	case strings.HasPrefix(name, database+"/operations/"):
+		polls := s.operations[name]
+		s.operations[name] = polls + 1
+		index := s.indexes[strings.Replace(name, "/operations/", "/collectionGroups/users/indexes/", 1)]
+		if polls > 0 {
+			index.State = "READY"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name": name,
+			"done": polls > 0,
+			"metadata": map[string]interface{}{
+				"index":             index.Name,
+				"progressDocuments": map[string]interface{}{"completedWork": fmt.Sprint(polls * 50), "estimatedWork": "100"},
+			},
+			"response": index,
+		})
+	case strings.HasSuffix(name, "/indexes") && r.Method == "POST":
+		var index firestoreIndex
+		json.NewDecoder(r.Body).Decode(&index)
+		id := fmt.Sprintf("i%d", len(s.indexes)+1)
+		index.Name = name + "/" + id
+		index.State = "CREATING"
+		if last := index.Fields[len(index.Fields)-1]; last.FieldPath != "__name__" {
+			index.Fields = append(index.Fields, firestoreIndexField{FieldPath: "__name__", Order: "ASCENDING"})
+		}
+		s.indexes[index.Name] = &index
+		op := database + "/operations/" + id
+		s.operations[op] = 0
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     op,
+			"metadata": map[string]interface{}{"index": index.Name, "state": "INITIALIZING"},
+		})
+	case strings.Contains(name, "/indexes/"):
+		index := s.indexes[name]
+		if index == nil {
+			fail(http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		if r.Method == "DELETE" {
+			delete(s.indexes, name)
+			fmt.Fprint(w, "{}")
+			return
+		}
+		json.NewEncoder(w).Encode(index)
+	case strings.Contains(name, "/fields/"):
+		field := s.fields[name]
+		if field == nil {
+			field = map[string]interface{}{"name": name, "indexConfig": map[string]interface{}{"usesAncestorConfig": true}}
+			s.fields[name] = field
+		}
+		if r.Method == "PATCH" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			mask := r.URL.Query().Get("updateMask")
+			s.masks = append(s.masks, mask)
+			for _, k := range strings.Split(mask, ",") {
+				delete(field, k)
+				if v, ok := body[k]; ok {
+					field[k] = v
+				}
+			}
+			if ttl, ok := field["ttlConfig"].(map[string]interface{}); ok {
+				ttl["state"] = "CREATING"
+			}
+			if field["indexConfig"] == nil {
+				field["indexConfig"] = map[string]interface{}{"usesAncestorConfig": true}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name": database + "/operations/field",
+				"done": true,
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(field)
+	default:
+		fail(http.StatusBadRequest, "unexpected request")
+	}
+}
+
+func newTestFirestoreServer() *testFirestoreServer {
+	return &testFirestoreServer{
+		indexes:    make(map[string]*firestoreIndex),
+		fields:     make(map[string]map[string]interface{}),
+		operations: make(map[string]int),
+	}
+}
+
+func TestResourceFirebaseFirestoreIndex(t *testing.T) {
+	fake := newTestFirestoreServer()
+	meta, closeServer := testAPIClient(t, fake, firestoreEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseFirestoreIndex()
+	_, err := r.Diff(nil, testResourceConfig(t, map[string]interface{}{
+		"collection": "users",
+		"field": []interface{}{
+			map[string]interface{}{"field_path": "tags", "order": "ASCENDING", "array_config": "CONTAINS"},
+			map[string]interface{}{"field_path": "age", "order": "DESCENDING"},
+		},
+	}), meta)
+	if err == nil || !strings.Contains(err.Error(), "exactly one of order or array_config") {
+		t.Fatalf("expected field error, got: %v", err)
+	}
+
+	diff, err := r.Diff(nil, testResourceConfig(t, map[string]interface{}{
+		"collection":  "users",
+		"query_scope": "COLLECTION_GROUP",
+		"field": []interface{}{
+			map[string]interface{}{"field_path": "tags", "array_config": "CONTAINS"},
+			map[string]interface{}{"field_path": "age", "order": "DESCENDING"},
+		},
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	name := fmt.Sprintf("projects/%s/databases/(default)/collectionGroups/users/indexes/i1", testProjectID)
+	if state.ID != name || state.Attributes["state"] != "READY" || state.Attributes["query_scope"] != "COLLECTION_GROUP" {
+		t.Fatalf("incorrect index: %v", state)
+	}
+	// The document name appended by Firestore is not a change
+	if state.Attributes["field.#"] != "2" || state.Attributes["field.1.field_path"] != "age" || len(fake.indexes[name].Fields) != 3 {
+		t.Fatalf("incorrect fields: %v", state.Attributes)
+	}
+
+	d := r.Data(state)
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.indexes) != 0 {
+		t.Fatalf("index not deleted")
+	}
+}
+
+func TestResourceFirebaseFirestoreFieldOverride(t *testing.T) {
+	fake := newTestFirestoreServer()
+	meta, closeServer := testAPIClient(t, fake, firestoreEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseFirestoreFieldOverride()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"collection": "events",
+		"field":      "expireAt",
+		"ttl":        true,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	name := fmt.Sprintf("projects/%s/databases/(default)/collectionGroups/events/fields/expireAt", testProjectID)
+	if d.Id() != name || d.Get("ttl_state") != "CREATING" || d.Get("use_default_indexes") != false {
+		t.Fatalf("incorrect field override: %s %v %v", d.Id(), d.Get("ttl_state"), d.Get("use_default_indexes"))
+	}
+	// Without indexes the field is exempted from indexing
+	config := fake.fields[name]["indexConfig"].(map[string]interface{})
+	if indexes, ok := config["indexes"].([]interface{}); !ok || len(indexes) != 0 {
+		t.Fatalf("field not exempted: %v", config)
+	}
+
+	diff, err := r.Diff(d.State(), testResourceConfig(t, map[string]interface{}{
+		"collection": "events",
+		"field":      "expireAt",
+		"ttl":        true,
+		"index": []interface{}{
+			map[string]interface{}{"order": "ASCENDING"},
+		},
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.masks[1] != "indexConfig" || state.Attributes["index.#"] != "1" || state.Attributes["ttl"] != "true" {
+		t.Fatalf("incorrect update: %v %v", fake.masks, state.Attributes)
+	}
+
+	// Deleting restores the automatic indexes and removes the TTL policy
+	d = r.Data(state)
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.fields[name]["ttlConfig"] != nil || fake.fields[name]["indexConfig"].(map[string]interface{})["usesAncestorConfig"] != true {
+		t.Fatalf("field not restored: %v", fake.fields[name])
+	}
+}
@@ -0,0 +1,217 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type hostingChannel struct {
+	Name                 string            `json:"name,omitempty"`
+	URL                  string            `json:"url,omitempty"`
+	ExpireTime           string            `json:"expireTime,omitempty"`
+	TTL                  string            `json:"ttl,omitempty"`
+	RetainedReleaseCount int               `json:"retainedReleaseCount,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+}
+
+// hostingChannelFields maps the schema to the channel fields for update
+// masks.
+var hostingChannelFields = map[string]string{
+	"ttl":                    "ttl",
+	"retained_release_count": "retainedReleaseCount",
+	"labels":                 "labels",
+}
+
+func resourceFirebaseHostingChannel() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseHostingChannelCreate,
+		Read:   resourceFirebaseHostingChannelRead,
+		Update: resourceFirebaseHostingChannelUpdate,
+		Delete: resourceFirebaseHostingChannelDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseHostingChannelImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"site_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingSiteID,
+			},
+			"channel_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingChannelID,
+			},
+			"ttl": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+			},
+			"retained_release_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expire_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func hostingChannelURL(project, site, channel string) string {
+	return fmt.Sprintf("%s/channels/%s", hostingSiteURL(project, site), channel)
+}
+
+func resourceFirebaseHostingChannelCreate(d *schema.ResourceData, meta interface{}) error {
+	site := d.Get("site_id").(string)
+	channel := d.Get("channel_id").(string)
+	log.Printf("[INFO] Creating Hosting channel %s of site: %s", channel, site)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/channels?channelId=%s", hostingSiteURL(client.ProjectID, site), channel)
+
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, expandHostingChannel(d), nil)
+	if err != nil {
+		return fmt.Errorf("Error creating Hosting channel (%s/%s): %s", site, channel, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", site, channel))
+
+	return resourceFirebaseHostingChannelRead(d, meta)
+}
+
+func resourceFirebaseHostingChannelRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Hosting channel: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	site, channel := d.Get("site_id").(string), d.Get("channel_id").(string)
+
+	var c hostingChannel
+	err = sendRequest(context.Background(), client.HTTP, "GET", hostingChannelURL(client.ProjectID, site, channel), nil, &c)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Hosting channel (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Hosting channel (%s): %s", d.Id(), err)
+	}
+
+	// The TTL is input only, the channel reports when it expires instead
+	d.Set("retained_release_count", c.RetainedReleaseCount)
+	d.Set("labels", c.Labels)
+	d.Set("name", c.Name)
+	d.Set("url", c.URL)
+	d.Set("expire_time", c.ExpireTime)
+
+	return nil
+}
+
+func resourceFirebaseHostingChannelUpdate(d *schema.ResourceData, meta interface{}) error {
+	var mask []string
+	for k, field := range hostingChannelFields {
+		if d.HasChange(k) {
+			mask = append(mask, field)
+		}
+	}
+
+	if len(mask) > 0 {
+		log.Printf("[INFO] Updating Hosting channel %s: %v", d.Id(), mask)
+
+		client, err := projectClient(d, meta)
+		if err != nil {
+			return err
+		}
+		url := fmt.Sprintf("%s?updateMask=%s",
+			hostingChannelURL(client.ProjectID, d.Get("site_id").(string), d.Get("channel_id").(string)),
+			strings.Join(mask, ","))
+
+		err = sendRequest(context.Background(), client.HTTP, "PATCH", url, expandHostingChannel(d), nil)
+		if err != nil {
+			return fmt.Errorf("Error updating Hosting channel (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceFirebaseHostingChannelRead(d, meta)
+}
+
+func resourceFirebaseHostingChannelDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Hosting channel: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := hostingChannelURL(client.ProjectID, d.Get("site_id").(string), d.Get("channel_id").(string))
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", url, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting Hosting channel (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseHostingChannelImportState accepts <site_id>/<channel_id>
+// or the channel's name, projects/<project>/sites/<site_id>/channels/<channel_id>.
+func resourceFirebaseHostingChannelImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "sites" && parts[4] == "channels":
+		d.Set("project", parts[1])
+		parts = []string{parts[3], parts[5]}
+	default:
+		return nil, fmt.Errorf("Import ID %q should be <site_id>/<channel_id> or projects/<project>/sites/<site_id>/channels/<channel_id>", d.Id())
+	}
+
+	d.SetId(strings.Join(parts, "/"))
+	d.Set("site_id", parts[0])
+	d.Set("channel_id", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandHostingChannel(d *schema.ResourceData) *hostingChannel {
+	c := &hostingChannel{
+		RetainedReleaseCount: d.Get("retained_release_count").(int),
+		Labels:               make(map[string]string),
+	}
+	if ttl, err := time.ParseDuration(d.Get("ttl").(string)); err == nil {
+		c.TTL = fmt.Sprintf("%ds", int64(ttl.Seconds()))
+	}
+	for k, v := range d.Get("labels").(map[string]interface{}) {
+		c.Labels[k] = v.(string)
+	}
+	return c
+}
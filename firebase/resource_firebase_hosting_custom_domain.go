@@ -0,0 +1,436 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type hostingCustomDomain struct {
+	Name               string            `json:"name,omitempty"`
+	CertPreference     string            `json:"certPreference,omitempty"`
+	RedirectTarget     string            `json:"redirectTarget,omitempty"`
+	HostState          string            `json:"hostState,omitempty"`
+	OwnershipState     string            `json:"ownershipState,omitempty"`
+	Reconciling        bool              `json:"reconciling,omitempty"`
+	DeleteTime         string            `json:"deleteTime,omitempty"`
+	Issues             []operationError  `json:"issues,omitempty"`
+	Cert               *hostingCert      `json:"cert,omitempty"`
+	RequiredDNSUpdates *hostingDNSUpdate `json:"requiredDnsUpdates,omitempty"`
+}
+
+type hostingCert struct {
+	Type   string           `json:"type"`
+	State  string           `json:"state"`
+	Issues []operationError `json:"issues"`
+}
+
+type hostingDNSUpdate struct {
+	Desired []struct {
+		DomainName string `json:"domainName"`
+		Records    []struct {
+			DomainName     string `json:"domainName"`
+			Type           string `json:"type"`
+			Rdata          string `json:"rdata"`
+			RequiredAction string `json:"requiredAction"`
+		} `json:"records"`
+		CheckError *operationError `json:"checkError"`
+	} `json:"desired"`
+}
+
+// hostingCustomDomainFields maps the schema to the custom domain fields for
+// update masks.
+var hostingCustomDomainFields = map[string]string{
+	"cert_preference": "certPreference",
+	"redirect_target": "redirectTarget",
+}
+
+func resourceFirebaseHostingCustomDomain() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseHostingCustomDomainCreate,
+		Read:   resourceFirebaseHostingCustomDomainRead,
+		Update: resourceFirebaseHostingCustomDomainUpdate,
+		Delete: resourceFirebaseHostingCustomDomainDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseHostingCustomDomainImportState,
+		},
+
+		// Certificates may take a while to be provisioned
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"site_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingSiteID,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					return strings.TrimSuffix(strings.ToLower(v.(string)), ".")
+				},
+			},
+			"cert_preference": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"GROUPED", "PROJECT_GROUPED", "DEDICATED",
+				}, false),
+			},
+			"redirect_target": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Only wait when the DNS records are already in place, as
+			// records fed from this resource are created after it
+			"wait_for_active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"host_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ownership_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cert_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"dns_records": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rdata": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"required_action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"issues": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func hostingCustomDomainURL(project, site, domain string) string {
+	return fmt.Sprintf("%s/customDomains/%s", hostingSiteURL(project, site), domain)
+}
+
+func resourceFirebaseHostingCustomDomainCreate(d *schema.ResourceData, meta interface{}) error {
+	site := d.Get("site_id").(string)
+	domain := strings.TrimSuffix(strings.ToLower(d.Get("domain_name").(string)), ".")
+	log.Printf("[INFO] Adding custom domain %s to Hosting site: %s", domain, site)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/customDomains?customDomainId=%s", hostingSiteURL(client.ProjectID, site), domain)
+
+	var op operation
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, expandHostingCustomDomain(d), &op)
+	if err != nil {
+		return fmt.Errorf("Error adding custom domain (%s): %s", domain, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", site, domain))
+	d.Set("domain_name", domain)
+
+	err = waitForOperation(client.HTTP, hostingEndpoint, &op, d.Timeout(schema.TimeoutCreate), nil)
+	if err != nil {
+		return fmt.Errorf("Error adding custom domain (%s): %s", domain, err)
+	}
+
+	if d.Get("wait_for_active").(bool) {
+		if err := waitForHostingCustomDomain(d, client, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceFirebaseHostingCustomDomainRead(d, meta)
+}
+
+func resourceFirebaseHostingCustomDomainRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Hosting custom domain: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	site, domain := d.Get("site_id").(string), d.Get("domain_name").(string)
+
+	var c hostingCustomDomain
+	err = sendRequest(context.Background(), client.HTTP, "GET", hostingCustomDomainURL(client.ProjectID, site, domain), nil, &c)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Hosting custom domain (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Hosting custom domain (%s): %s", d.Id(), err)
+	}
+	// Deleted custom domains are kept for 30 days before being purged
+	if c.DeleteTime != "" {
+		log.Printf("[WARN] Hosting custom domain (%s) is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("cert_preference", c.CertPreference)
+	d.Set("redirect_target", c.RedirectTarget)
+	d.Set("name", c.Name)
+	d.Set("host_state", c.HostState)
+	d.Set("ownership_state", c.OwnershipState)
+	d.Set("cert_state", "")
+	if c.Cert != nil {
+		d.Set("cert_state", c.Cert.State)
+	}
+	if err := d.Set("dns_records", flattenHostingDNSRecords(c.RequiredDNSUpdates)); err != nil {
+		return fmt.Errorf("Error setting dns_records: %s", err)
+	}
+	d.Set("issues", hostingCustomDomainIssues(&c))
+
+	return nil
+}
+
+func resourceFirebaseHostingCustomDomainUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var mask []string
+	for k, field := range hostingCustomDomainFields {
+		if d.HasChange(k) {
+			mask = append(mask, field)
+		}
+	}
+
+	if len(mask) > 0 {
+		log.Printf("[INFO] Updating Hosting custom domain %s: %v", d.Id(), mask)
+
+		url := fmt.Sprintf("%s?updateMask=%s",
+			hostingCustomDomainURL(client.ProjectID, d.Get("site_id").(string), d.Get("domain_name").(string)),
+			strings.Join(mask, ","))
+
+		var op operation
+		err = sendRequest(context.Background(), client.HTTP, "PATCH", url, expandHostingCustomDomain(d), &op)
+		if err != nil {
+			return fmt.Errorf("Error updating Hosting custom domain (%s): %s", d.Id(), err)
+		}
+		err = waitForOperation(client.HTTP, hostingEndpoint, &op, d.Timeout(schema.TimeoutUpdate), nil)
+		if err != nil {
+			return fmt.Errorf("Error updating Hosting custom domain (%s): %s", d.Id(), err)
+		}
+	}
+
+	if d.Get("wait_for_active").(bool) {
+		if err := waitForHostingCustomDomain(d, client, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+
+	return resourceFirebaseHostingCustomDomainRead(d, meta)
+}
+
+func resourceFirebaseHostingCustomDomainDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Hosting custom domain: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := hostingCustomDomainURL(client.ProjectID, d.Get("site_id").(string), d.Get("domain_name").(string))
+
+	var op operation
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", url, nil, &op)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("Error deleting Hosting custom domain (%s): %s", d.Id(), err)
+	}
+
+	err = waitForOperation(client.HTTP, hostingEndpoint, &op, d.Timeout(schema.TimeoutDelete), nil)
+	if err != nil {
+		return fmt.Errorf("Error deleting Hosting custom domain (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseHostingCustomDomainImportState accepts <site_id>/<domain>
+// or the custom domain's name,
+// projects/<project>/sites/<site_id>/customDomains/<domain>.
+func resourceFirebaseHostingCustomDomainImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "sites" && parts[4] == "customDomains":
+		d.Set("project", parts[1])
+		parts = []string{parts[3], parts[5]}
+	default:
+		return nil, fmt.Errorf("Import ID %q should be <site_id>/<domain> or projects/<project>/sites/<site_id>/customDomains/<domain>", d.Id())
+	}
+
+	d.SetId(strings.Join(parts, "/"))
+	d.Set("site_id", parts[0])
+	d.Set("domain_name", parts[1])
+	d.Set("wait_for_active", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// waitForHostingCustomDomain waits until Hosting serves the domain with an
+// active certificate, or until the domain needs changes to its DNS records
+// or another project's records to be resolved.
+func waitForHostingCustomDomain(d *schema.ResourceData, client Client, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for Hosting custom domain (%s) to become active", d.Id())
+
+	url := hostingCustomDomainURL(client.ProjectID, d.Get("site_id").(string), d.Get("domain_name").(string))
+	var c hostingCustomDomain
+
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"active"},
+		Refresh: func() (interface{}, string, error) {
+			c = hostingCustomDomain{}
+			if err := sendRequest(context.Background(), client.HTTP, "GET", url, nil, &c); err != nil {
+				return nil, "", err
+			}
+			state := hostingCustomDomainState(&c)
+			if state == "failed" {
+				return nil, "", fmt.Errorf("%s", hostingCustomDomainStatus(&c))
+			}
+			return &c, state, nil
+		},
+		Timeout:    timeout,
+		Delay:      pollDelay,
+		MinTimeout: pollMinTimeout,
+	}
+
+	if _, err := stateConf.WaitForState(); err != nil {
+		if _, ok := err.(*resource.TimeoutError); ok {
+			err = fmt.Errorf("%s, last seen %s", err, hostingCustomDomainStatus(&c))
+		}
+		return fmt.Errorf("Error waiting for Hosting custom domain (%s) to become active: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// hostingCustomDomainState reduces the domain's host, ownership and
+// certificate states to active, pending while Hosting makes progress on its
+// own, or failed.
+func hostingCustomDomainState(c *hostingCustomDomain) string {
+	cert := ""
+	if c.Cert != nil {
+		cert = c.Cert.State
+	}
+	switch {
+	case c.Reconciling:
+		return "pending"
+	case c.HostState == "HOST_ACTIVE" && c.OwnershipState == "OWNERSHIP_ACTIVE" &&
+		(cert == "CERT_ACTIVE" || cert == "CERT_EXPIRING_SOON"):
+		return "active"
+	case c.HostState == "HOST_UNREACHABLE", c.OwnershipState == "OWNERSHIP_PENDING",
+		c.OwnershipState == "OWNERSHIP_UNREACHABLE", cert == "CERT_PREPARING",
+		cert == "CERT_VALIDATING", cert == "CERT_PROPAGATING":
+		return "pending"
+	}
+	return "failed"
+}
+
+func hostingCustomDomainStatus(c *hostingCustomDomain) string {
+	status := fmt.Sprintf("host %s, ownership %s", c.HostState, c.OwnershipState)
+	if c.Cert != nil {
+		status += fmt.Sprintf(", certificate %s", c.Cert.State)
+	}
+	if issues := hostingCustomDomainIssues(c); len(issues) > 0 {
+		status += ": " + strings.Join(issues, "; ")
+	}
+	return status
+}
+
+func hostingCustomDomainIssues(c *hostingCustomDomain) []string {
+	var issues []string
+	for _, i := range c.Issues {
+		issues = append(issues, i.Message)
+	}
+	if c.Cert != nil {
+		for _, i := range c.Cert.Issues {
+			issues = append(issues, i.Message)
+		}
+	}
+	if c.RequiredDNSUpdates != nil {
+		for _, desired := range c.RequiredDNSUpdates.Desired {
+			if desired.CheckError != nil {
+				issues = append(issues, desired.CheckError.Message)
+			}
+		}
+	}
+	return issues
+}
+
+func flattenHostingDNSRecords(u *hostingDNSUpdate) []map[string]interface{} {
+	records := make([]map[string]interface{}, 0)
+	if u == nil {
+		return records
+	}
+	for _, desired := range u.Desired {
+		for _, r := range desired.Records {
+			records = append(records, map[string]interface{}{
+				"domain_name":     r.DomainName,
+				"type":            r.Type,
+				"rdata":           r.Rdata,
+				"required_action": r.RequiredAction,
+			})
+		}
+	}
+	return records
+}
+
+func expandHostingCustomDomain(d *schema.ResourceData) *hostingCustomDomain {
+	return &hostingCustomDomain{
+		CertPreference: d.Get("cert_preference").(string),
+		RedirectTarget: d.Get("redirect_target").(string),
+	}
+}
@@ -0,0 +1,205 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testCustomDomainServer fakes the custom domains of a Hosting site. Every
+// read of a domain moves it to its next state.
+type testCustomDomainServer struct {
+	sync.Mutex
+	domains map[string]map[string]interface{}
+	states  []map[string]interface{}
+	masks   []string
+}
+
+func (s *testCustomDomainServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1beta1/")
+	done := func(response interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     name + "/operations/1",
+			"done":     true,
+			"response": response,
+		})
+	}
+
+	if r.Method == "POST" {
+		var domain map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&domain)
+		name += "/" + r.URL.Query().Get("customDomainId")
+		domain["name"] = name
+		domain["reconciling"] = true
+		domain["requiredDnsUpdates"] = map[string]interface{}{
+			"desired": []interface{}{map[string]interface{}{
+				"domainName": r.URL.Query().Get("customDomainId"),
+				"records": []interface{}{
+					map[string]interface{}{"domainName": "www.acme.test", "type": "TXT", "rdata": "hosting-site=acme-marketing", "requiredAction": "ADD"},
+					map[string]interface{}{"domainName": "www.acme.test", "type": "A", "rdata": "199.36.158.100", "requiredAction": "ADD"},
+				},
+			}},
+		}
+		s.domains[name] = domain
+		done(domain)
+		return
+	}
+
+	domain := s.domains[name]
+	if domain == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"NOT_FOUND"}}`)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		if len(s.states) > 0 {
+			for k, v := range s.states[0] {
+				domain[k] = v
+			}
+			s.states = s.states[1:]
+		}
+		json.NewEncoder(w).Encode(domain)
+	case "PATCH":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mask := r.URL.Query().Get("updateMask")
+		s.masks = append(s.masks, mask)
+		for _, field := range strings.Split(mask, ",") {
+			domain[field] = body[field]
+		}
+		done(domain)
+	case "DELETE":
+		delete(s.domains, name)
+		done(struct{}{})
+	}
+}
+
+func newTestCustomDomainServer(states ...map[string]interface{}) *testCustomDomainServer {
+	return &testCustomDomainServer{domains: make(map[string]map[string]interface{}), states: states}
+}
+
+func TestResourceFirebaseHostingCustomDomain(t *testing.T) {
+	fake := newTestCustomDomainServer(
+		map[string]interface{}{
+			"reconciling":    false,
+			"hostState":      "HOST_ACTIVE",
+			"ownershipState": "OWNERSHIP_PENDING",
+			"cert":           map[string]interface{}{"type": "GROUPED", "state": "CERT_PREPARING"},
+		},
+		map[string]interface{}{
+			"ownershipState": "OWNERSHIP_ACTIVE",
+			"cert":           map[string]interface{}{"type": "GROUPED", "state": "CERT_ACTIVE"},
+		},
+	)
+	meta, closeServer := testAPIClient(t, fake, hostingEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseHostingCustomDomain()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"site_id":         "acme-marketing",
+		"domain_name":     "WWW.acme.test.",
+		"wait_for_active": true,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "acme-marketing/www.acme.test" {
+		t.Fatalf("incorrect id: %s", d.Id())
+	}
+	if d.Get("ownership_state") != "OWNERSHIP_ACTIVE" || d.Get("cert_state") != "CERT_ACTIVE" {
+		t.Fatalf("custom domain is not active: %s %s", d.Get("ownership_state"), d.Get("cert_state"))
+	}
+	if d.Get("dns_records.#") != 2 || d.Get("dns_records.0.type") != "TXT" || d.Get("dns_records.1.rdata") != "199.36.158.100" {
+		t.Fatalf("incorrect dns_records: %v", d.Get("dns_records"))
+	}
+
+	states, err := r.Importer.State(r.Data(&terraform.InstanceState{
+		ID: fmt.Sprintf("projects/%s/sites/acme-marketing/customDomains/www.acme.test", testProjectID),
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if states[0].Id() != d.Id() || states[0].Get("domain_name") != "www.acme.test" {
+		t.Fatalf("incorrect import: %s", states[0].Id())
+	}
+
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.domains) != 0 {
+		t.Fatalf("custom domain was not deleted")
+	}
+}
+
+func TestResourceFirebaseHostingCustomDomain_failed(t *testing.T) {
+	meta, closeServer := testAPIClient(t, newTestCustomDomainServer(
+		map[string]interface{}{
+			"reconciling":    false,
+			"hostState":      "HOST_MISMATCH",
+			"ownershipState": "OWNERSHIP_MISSING",
+			"issues": []interface{}{
+				map[string]interface{}{"code": 9, "message": "No TXT record found for www.acme.test"},
+			},
+		},
+	), hostingEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseHostingCustomDomain()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"site_id":         "acme-marketing",
+		"domain_name":     "www.acme.test",
+		"wait_for_active": true,
+	})
+	err := r.Create(d, meta)
+	if err == nil {
+		t.Fatalf("expected failed custom domain")
+	}
+	if !strings.Contains(err.Error(), "ownership OWNERSHIP_MISSING") || !strings.Contains(err.Error(), "No TXT record found for www.acme.test") {
+		t.Fatalf("error without the status messages: %s", err)
+	}
+	// The domain was added, so it is kept in state to be fixed or replaced
+	if d.Id() != "acme-marketing/www.acme.test" {
+		t.Fatalf("added custom domain not kept in state")
+	}
+}
+
+func TestResourceFirebaseHostingCustomDomain_update(t *testing.T) {
+	fake := newTestCustomDomainServer()
+	meta, closeServer := testAPIClient(t, fake, hostingEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseHostingCustomDomain()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"site_id":     "acme-marketing",
+		"domain_name": "acme.test",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	diff, err := r.Diff(d.State(), testResourceConfig(t, map[string]interface{}{
+		"site_id":         "acme-marketing",
+		"domain_name":     "acme.test",
+		"redirect_target": "www.acme.test",
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.masks) != 1 || fake.masks[0] != "redirectTarget" || state.Attributes["redirect_target"] != "www.acme.test" {
+		t.Fatalf("incorrect update: %v %s", fake.masks, state.Attributes["redirect_target"])
+	}
+}
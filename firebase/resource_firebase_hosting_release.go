@@ -0,0 +1,116 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type hostingRelease struct {
+	Name        string `json:"name,omitempty"`
+	Message     string `json:"message,omitempty"`
+	ReleaseTime string `json:"releaseTime,omitempty"`
+}
+
+func resourceFirebaseHostingRelease() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseHostingReleaseCreate,
+		Read:   resourceFirebaseHostingReleaseRead,
+		Delete: resourceFirebaseHostingReleaseDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"site_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingSiteID,
+			},
+			"channel_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingChannelID,
+			},
+			"version_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"message": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"release_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseHostingReleaseCreate(d *schema.ResourceData, meta interface{}) error {
+	site := d.Get("site_id").(string)
+	version := d.Get("version_name").(string)
+	log.Printf("[INFO] Releasing Hosting version: %s", version)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Without a channel the version goes live
+	u := hostingSiteURL(client.ProjectID, site)
+	if channel := d.Get("channel_id").(string); channel != "" {
+		u = hostingChannelURL(client.ProjectID, site, channel)
+	}
+	u = fmt.Sprintf("%s/releases?versionName=%s", u, url.QueryEscape(version))
+
+	var release hostingRelease
+	err = sendRequest(context.Background(), client.HTTP, "POST", u, &hostingRelease{Message: d.Get("message").(string)}, &release)
+	if err != nil {
+		return fmt.Errorf("Error releasing Hosting version (%s): %s", version, err)
+	}
+
+	d.SetId(release.Name)
+	log.Printf("[INFO] Hosting release: %s", d.Id())
+
+	return resourceFirebaseHostingReleaseRead(d, meta)
+}
+
+func resourceFirebaseHostingReleaseRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Hosting release: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var release hostingRelease
+	err = sendRequest(context.Background(), client.HTTP, "GET", hostingEndpoint+"/"+d.Id(), nil, &release)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Hosting release (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Hosting release (%s): %s", d.Id(), err)
+	}
+
+	d.Set("message", release.Message)
+	d.Set("release_time", release.ReleaseTime)
+
+	return nil
+}
+
+func resourceFirebaseHostingReleaseDelete(d *schema.ResourceData, meta interface{}) error {
+	// Releases are a history that cannot be rewritten, the next release
+	// replaces this one
+	log.Printf("[INFO] Forgetting Hosting release: %s", d.Id())
+	d.SetId("")
+	return nil
+}
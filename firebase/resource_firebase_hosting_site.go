@@ -0,0 +1,153 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const hostingEndpoint = "https://firebasehosting.googleapis.com/v1beta1"
+
+type hostingSite struct {
+	Name       string `json:"name,omitempty"`
+	DefaultURL string `json:"defaultUrl,omitempty"`
+	AppID      string `json:"appId"`
+}
+
+func hostingSiteURL(project, site string) string {
+	return fmt.Sprintf("%s/projects/%s/sites/%s", hostingEndpoint, project, site)
+}
+
+func resourceFirebaseHostingSite() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseHostingSiteCreate,
+		Read:   resourceFirebaseHostingSiteRead,
+		Update: resourceFirebaseHostingSiteUpdate,
+		Delete: resourceFirebaseHostingSiteDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseHostingSiteImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"site_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingSiteID,
+			},
+			"app_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseHostingSiteCreate(d *schema.ResourceData, meta interface{}) error {
+	siteID := d.Get("site_id").(string)
+	log.Printf("[INFO] Creating Hosting site: %s", siteID)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/projects/%s/sites?siteId=%s", hostingEndpoint, client.ProjectID, siteID)
+
+	site := &hostingSite{AppID: d.Get("app_id").(string)}
+	if err := sendRequest(context.Background(), client.HTTP, "POST", url, site, nil); err != nil {
+		return fmt.Errorf("Error creating Hosting site (%s): %s", siteID, err)
+	}
+
+	d.SetId(siteID)
+
+	return resourceFirebaseHostingSiteRead(d, meta)
+}
+
+func resourceFirebaseHostingSiteRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Hosting site: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var site hostingSite
+	err = sendRequest(context.Background(), client.HTTP, "GET", hostingSiteURL(client.ProjectID, d.Id()), nil, &site)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Hosting site (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Hosting site (%s): %s", d.Id(), err)
+	}
+
+	d.Set("site_id", d.Id())
+	d.Set("app_id", site.AppID)
+	d.Set("name", site.Name)
+	d.Set("default_url", site.DefaultURL)
+
+	return nil
+}
+
+func resourceFirebaseHostingSiteUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("app_id") {
+		log.Printf("[INFO] Updating app of Hosting site: %s", d.Id())
+
+		client, err := projectClient(d, meta)
+		if err != nil {
+			return err
+		}
+		url := hostingSiteURL(client.ProjectID, d.Id()) + "?updateMask=appId"
+
+		site := &hostingSite{AppID: d.Get("app_id").(string)}
+		if err := sendRequest(context.Background(), client.HTTP, "PATCH", url, site, nil); err != nil {
+			return fmt.Errorf("Error updating Hosting site (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceFirebaseHostingSiteRead(d, meta)
+}
+
+func resourceFirebaseHostingSiteDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Hosting site: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", hostingSiteURL(client.ProjectID, d.Id()), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting Hosting site (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseHostingSiteImportState accepts a site ID or the site's
+// name, projects/<project>/sites/<site_id>.
+func resourceFirebaseHostingSiteImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	switch {
+	case len(parts) == 1:
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "sites":
+		d.Set("project", parts[1])
+		d.SetId(parts[3])
+	default:
+		return nil, fmt.Errorf("Import ID %q should be a site ID or projects/<project>/sites/<site_id>", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
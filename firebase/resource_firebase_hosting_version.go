@@ -0,0 +1,506 @@
+package firebase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"google.golang.org/api/googleapi"
+)
+
+// hostingPopulateBatchSize is the number of files registered with a version
+// per populateFiles call.
+const hostingPopulateBatchSize = 1000
+
+type hostingVersion struct {
+	Name   string                `json:"name,omitempty"`
+	Status string                `json:"status,omitempty"`
+	Config *hostingVersionConfig `json:"config,omitempty"`
+}
+
+type hostingVersionConfig struct {
+	Redirects             []hostingRedirect `json:"redirects,omitempty"`
+	Rewrites              []hostingRewrite  `json:"rewrites,omitempty"`
+	Headers               []hostingHeader   `json:"headers,omitempty"`
+	CleanURLs             bool              `json:"cleanUrls,omitempty"`
+	TrailingSlashBehavior string            `json:"trailingSlashBehavior,omitempty"`
+}
+
+type hostingRedirect struct {
+	Glob       string `json:"glob,omitempty"`
+	Regex      string `json:"regex,omitempty"`
+	StatusCode int    `json:"statusCode"`
+	Location   string `json:"location"`
+}
+
+type hostingRewrite struct {
+	Glob     string `json:"glob,omitempty"`
+	Regex    string `json:"regex,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Function string `json:"function,omitempty"`
+}
+
+type hostingHeader struct {
+	Glob    string            `json:"glob,omitempty"`
+	Regex   string            `json:"regex,omitempty"`
+	Headers map[string]string `json:"headers"`
+}
+
+// hostingFile is a file of a version, gzipped and hashed as the Hosting
+// API expects.
+type hostingFile struct {
+	source string
+	hash   string
+}
+
+func resourceFirebaseHostingVersion() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceFirebaseHostingVersionCreate,
+		Read:          resourceFirebaseHostingVersionRead,
+		Delete:        resourceFirebaseHostingVersionDelete,
+		CustomizeDiff: resourceFirebaseHostingVersionCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"site_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateHostingSiteID,
+			},
+			"public_dir": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"files": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"clean_urls": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"trailing_slash_behavior": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"ADD", "REMOVE"}, false),
+			},
+			"redirect": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"glob": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"regex": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"status_code": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      301,
+							ValidateFunc: validation.IntBetween(301, 302),
+						},
+						"location": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"rewrite": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"glob": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"regex": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"function": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"header": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"glob": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"regex": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"headers": {
+							Type:     schema.TypeMap,
+							Required: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"files_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"file_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseHostingVersionCreate(d *schema.ResourceData, meta interface{}) error {
+	site := d.Get("site_id").(string)
+	log.Printf("[INFO] Creating Hosting version of site: %s", site)
+
+	files, err := hostingVersionFiles(d.Get("public_dir").(string), d.Get("files").(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var version hostingVersion
+	url := hostingSiteURL(client.ProjectID, site) + "/versions"
+	err = sendRequest(ctx, client.HTTP, "POST", url, &hostingVersion{Config: expandHostingVersionConfig(d)}, &version)
+	if err != nil {
+		return fmt.Errorf("Error creating Hosting version of site (%s): %s", site, err)
+	}
+	d.SetId(version.Name)
+	log.Printf("[INFO] Hosting version: %s", d.Id())
+
+	if err := populateHostingVersion(ctx, client.HTTP, d.Id(), files); err != nil {
+		return fmt.Errorf("Error uploading files of Hosting version (%s): %s", d.Id(), err)
+	}
+
+	log.Printf("[INFO] Finalizing Hosting version: %s", d.Id())
+	url = fmt.Sprintf("%s/%s?update_mask=status", hostingEndpoint, d.Id())
+	err = sendRequest(ctx, client.HTTP, "PATCH", url, &hostingVersion{Status: "FINALIZED"}, nil)
+	if err != nil {
+		return fmt.Errorf("Error finalizing Hosting version (%s): %s", d.Id(), err)
+	}
+
+	d.Set("files_sha256", hostingFilesHash(files))
+	d.Set("file_count", len(files))
+
+	return resourceFirebaseHostingVersionRead(d, meta)
+}
+
+func resourceFirebaseHostingVersionRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Hosting version: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var version hostingVersion
+	err = sendRequest(context.Background(), client.HTTP, "GET", hostingEndpoint+"/"+d.Id(), nil, &version)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Hosting version (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Hosting version (%s): %s", d.Id(), err)
+	}
+
+	switch version.Status {
+	case "DELETED", "ABANDONED", "EXPIRED":
+		log.Printf("[WARN] Hosting version (%s) is %s, removing from state", d.Id(), version.Status)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("version_id", d.Id()[strings.LastIndex(d.Id(), "/")+1:])
+
+	return nil
+}
+
+func resourceFirebaseHostingVersionDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Hosting version: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", hostingEndpoint+"/"+d.Id(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting Hosting version (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseHostingVersionCustomizeDiff replaces the version when the
+// contents of its files change, which their paths alone do not show.
+func resourceFirebaseHostingVersionCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("public_dir") || !d.NewValueKnown("files") {
+		return d.SetNewComputed("files_sha256")
+	}
+
+	files, err := hostingVersionFiles(d.Get("public_dir").(string), d.Get("files").(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+	hash := hostingFilesHash(files)
+	if d.Id() == "" || hash == d.Get("files_sha256").(string) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Files of Hosting version %s have changed", d.Id())
+	if err := d.SetNew("files_sha256", hash); err != nil {
+		return err
+	}
+	return d.ForceNew("files_sha256")
+}
+
+// hostingVersionFiles returns the files of a version by their path on the
+// site. Files below publicDir are served relative to it, files maps
+// additional paths to local files.
+func hostingVersionFiles(publicDir string, files map[string]interface{}) (map[string]*hostingFile, error) {
+	result := make(map[string]*hostingFile)
+	add := func(path, source string) error {
+		hash, err := hashHostingFile(source)
+		if err != nil {
+			return err
+		}
+		result[path] = &hostingFile{source: source, hash: hash}
+		return nil
+	}
+
+	if publicDir != "" {
+		err := filepath.Walk(publicDir, func(source string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(publicDir, source)
+			if err != nil {
+				return err
+			}
+			return add("/"+filepath.ToSlash(rel), source)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error reading public_dir: %s", err)
+		}
+	}
+
+	for path, source := range files {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if err := add(path, source.(string)); err != nil {
+			return nil, fmt.Errorf("Error reading file (%s): %s", path, err)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("Hosting versions need at least one file in public_dir or files")
+	}
+	return result, nil
+}
+
+// gzipHostingFile compresses source without a timestamp, so its hash only
+// depends on its contents.
+func gzipHostingFile(source string) ([]byte, error) {
+	b, err := ioutil.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hashHostingFile(source string) (string, error) {
+	b, err := gzipHostingFile(source)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// hostingFilesHash digests the paths and hashes of all files of a version.
+func hostingFilesHash(files map[string]*hostingFile) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", path, files[path].hash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// populateHostingVersion registers files with the version and uploads the
+// ones the Hosting API does not have yet.
+func populateHostingVersion(ctx context.Context, client *http.Client, version string, files map[string]*hostingFile) error {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	sources := make(map[string]string, len(files))
+	for _, f := range files {
+		sources[f.hash] = f.source
+	}
+
+	for start := 0; start < len(paths); start += hostingPopulateBatchSize {
+		end := start + hostingPopulateBatchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		batch := make(map[string]string, end-start)
+		for _, path := range paths[start:end] {
+			batch[path] = files[path].hash
+		}
+
+		var res struct {
+			UploadRequiredHashes []string `json:"uploadRequiredHashes"`
+			UploadURL            string   `json:"uploadUrl"`
+		}
+		url := fmt.Sprintf("%s/%s:populateFiles", hostingEndpoint, version)
+		body := map[string]interface{}{"files": batch}
+		if err := sendRequest(ctx, client, "POST", url, body, &res); err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] Uploading %d of %d files", len(res.UploadRequiredHashes), len(batch))
+
+		for _, hash := range res.UploadRequiredHashes {
+			if err := uploadHostingFile(ctx, client, res.UploadURL+"/"+hash, sources[hash]); err != nil {
+				return fmt.Errorf("%s: %s", sources[hash], err)
+			}
+		}
+	}
+	return nil
+}
+
+func uploadHostingFile(ctx context.Context, client *http.Client, url, source string) error {
+	b, err := gzipHostingFile(source)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	log.Printf("[DEBUG] POST %s", url)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return googleapi.CheckResponse(res)
+}
+
+func expandHostingVersionConfig(d *schema.ResourceData) *hostingVersionConfig {
+	config := &hostingVersionConfig{
+		CleanURLs:             d.Get("clean_urls").(bool),
+		TrailingSlashBehavior: d.Get("trailing_slash_behavior").(string),
+	}
+	for _, v := range d.Get("redirect").([]interface{}) {
+		r := v.(map[string]interface{})
+		config.Redirects = append(config.Redirects, hostingRedirect{
+			Glob:       r["glob"].(string),
+			Regex:      r["regex"].(string),
+			StatusCode: r["status_code"].(int),
+			Location:   r["location"].(string),
+		})
+	}
+	for _, v := range d.Get("rewrite").([]interface{}) {
+		r := v.(map[string]interface{})
+		config.Rewrites = append(config.Rewrites, hostingRewrite{
+			Glob:     r["glob"].(string),
+			Regex:    r["regex"].(string),
+			Path:     r["path"].(string),
+			Function: r["function"].(string),
+		})
+	}
+	for _, v := range d.Get("header").([]interface{}) {
+		h := v.(map[string]interface{})
+		headers := make(map[string]string)
+		for k, v := range h["headers"].(map[string]interface{}) {
+			headers[k] = v.(string)
+		}
+		config.Headers = append(config.Headers, hostingHeader{
+			Glob:    h["glob"].(string),
+			Regex:   h["regex"].(string),
+			Headers: headers,
+		})
+	}
+	return config
+}
@@ -0,0 +1,308 @@
+package firebase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testHostingServer fakes the Hosting API sites, channels, versions and
+// releases, and the upload endpoint of version files.
+type testHostingServer struct {
+	sync.Mutex
+	url       string
+	resources map[string]map[string]interface{}
+	blobs     map[string][]byte
+	uploads   []string
+}
+
+// testHostingUploadURL is where the fake Hosting API uploads files.
+const testHostingUploadURL = "https://upload-firebasehosting.googleapis.com"
+
+func newTestHostingServer() *testHostingServer {
+	return &testHostingServer{
+		url:       testHostingUploadURL,
+		resources: make(map[string]map[string]interface{}),
+		blobs:     make(map[string][]byte),
+	}
+}
+
+func (s *testHostingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/upload/") {
+		s.handleUpload(w, r)
+		return
+	}
+	s.handle(w, r)
+}
+
+func (s *testHostingServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hash := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	if h := sha256.Sum256(b); hex.EncodeToString(h[:]) != hash {
+		http.Error(w, "hash mismatch", http.StatusBadRequest)
+		return
+	}
+	s.blobs[hash] = b
+	s.uploads = append(s.uploads, hash)
+}
+
+func (s *testHostingServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1beta1/")
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(name, ":populateFiles"):
+		version := strings.TrimSuffix(name, ":populateFiles")
+		files := s.resources[version]["files"].(map[string]interface{})
+		missing := make(map[string]bool)
+		for path, hash := range body["files"].(map[string]interface{}) {
+			files[path] = hash
+			if s.blobs[hash.(string)] == nil {
+				missing[hash.(string)] = true
+			}
+		}
+		required := []string{}
+		for hash := range missing {
+			required = append(required, hash)
+		}
+		sort.Strings(required)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"uploadRequiredHashes": required,
+			"uploadUrl":            s.url + "/upload/" + version + "/files",
+		})
+		return
+	case r.Method == "POST":
+		q := r.URL.Query()
+		switch {
+		case q.Get("siteId") != "":
+			name += "/" + q.Get("siteId")
+			body["defaultUrl"] = fmt.Sprintf("https://%s.web.app", q.Get("siteId"))
+		case q.Get("channelId") != "":
+			name += "/" + q.Get("channelId")
+			body["url"] = fmt.Sprintf("https://%s--%s.web.app", strings.Split(name, "/")[3], q.Get("channelId"))
+			body["expireTime"] = "2026-10-20T00:00:00Z"
+		case strings.HasSuffix(name, "/versions"):
+			name += fmt.Sprintf("/v%d", len(s.resources))
+			body["status"] = "CREATED"
+			body["files"] = map[string]interface{}{}
+		case strings.HasSuffix(name, "/releases"):
+			name += fmt.Sprintf("/r%d", len(s.resources))
+			body["version"] = s.resources[q.Get("versionName")]
+			body["releaseTime"] = "2026-10-19T00:00:00Z"
+		}
+		body["name"] = name
+		s.resources[name] = body
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	resource := s.resources[name]
+	if resource == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"NOT_FOUND"}}`)
+		return
+	}
+	switch r.Method {
+	case "PATCH":
+		mask := r.URL.Query().Get("updateMask") + r.URL.Query().Get("update_mask")
+		for _, field := range strings.Split(mask, ",") {
+			resource[field] = body[field]
+		}
+	case "DELETE":
+		delete(s.resources, name)
+	}
+	json.NewEncoder(w).Encode(resource)
+}
+
+func TestResourceFirebaseHostingSite(t *testing.T) {
+	fake := newTestHostingServer()
+	meta, closeServer := testAPIClient(t, fake, hostingEndpoint, testHostingUploadURL)
+	defer closeServer()
+
+	r := resourceFirebaseHostingSite()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"site_id": "acme-marketing",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "acme-marketing" || d.Get("default_url") != "https://acme-marketing.web.app" {
+		t.Fatalf("incorrect site: %s %s", d.Id(), d.Get("default_url"))
+	}
+
+	c := schema.TestResourceDataRaw(t, resourceFirebaseHostingChannel().Schema, map[string]interface{}{
+		"site_id":    "acme-marketing",
+		"channel_id": "preview",
+		"ttl":        "24h",
+	})
+	if err := resourceFirebaseHostingChannel().Create(c, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	name := fmt.Sprintf("projects/%s/sites/acme-marketing/channels/preview", testProjectID)
+	if c.Id() != "acme-marketing/preview" || fake.resources[name]["ttl"] != "86400s" {
+		t.Fatalf("incorrect channel: %s %v", c.Id(), fake.resources[name]["ttl"])
+	}
+	if c.Get("url") != "https://acme-marketing--preview.web.app" {
+		t.Fatalf("incorrect url: %s", c.Get("url"))
+	}
+
+	states, err := resourceFirebaseHostingChannel().Importer.State(resourceFirebaseHostingChannel().Data(&terraform.InstanceState{ID: name}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if states[0].Id() != "acme-marketing/preview" || states[0].Get("channel_id") != "preview" {
+		t.Fatalf("incorrect import: %s", states[0].Id())
+	}
+
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("deleted site was kept in state")
+	}
+}
+
+func TestResourceFirebaseHostingVersion(t *testing.T) {
+	fake := newTestHostingServer()
+	meta, closeServer := testAPIClient(t, fake, hostingEndpoint, testHostingUploadURL)
+	defer closeServer()
+
+	dir, err := ioutil.TempDir("", "terraform-provider-firebase")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	public := filepath.Join(dir, "public")
+	os.MkdirAll(filepath.Join(public, "css"), 0700)
+	ioutil.WriteFile(filepath.Join(public, "index.html"), []byte("<h1>Acme</h1>"), 0600)
+	ioutil.WriteFile(filepath.Join(public, "css", "main.css"), []byte("h1 {}"), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "404.html"), []byte("<h1>Acme</h1>"), 0600)
+
+	r := resourceFirebaseHostingVersion()
+	raw := map[string]interface{}{
+		"site_id":    "acme-marketing",
+		"public_dir": public,
+		"files": map[string]interface{}{
+			"/404.html": filepath.Join(dir, "404.html"),
+		},
+		"clean_urls": true,
+		"redirect": []interface{}{
+			map[string]interface{}{"glob": "/old", "location": "/"},
+		},
+		"rewrite": []interface{}{
+			map[string]interface{}{"glob": "/api/**", "function": "api"},
+		},
+		"header": []interface{}{
+			map[string]interface{}{"glob": "**/*.css", "headers": map[string]interface{}{"Cache-Control": "max-age=3600"}},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	version := fake.resources[d.Id()]
+	if version["status"] != "FINALIZED" || d.Get("file_count") != 3 {
+		t.Fatalf("incorrect version: %v %v", version["status"], d.Get("file_count"))
+	}
+	files := version["files"].(map[string]interface{})
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if fmt.Sprint(paths) != "[/404.html /css/main.css /index.html]" {
+		t.Fatalf("incorrect files: %v", paths)
+	}
+	// Identical files are uploaded once
+	if len(fake.uploads) != 2 || files["/404.html"] != files["/index.html"] {
+		t.Fatalf("incorrect uploads: %v", fake.uploads)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(fake.blobs[files["/index.html"].(string)]))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if b, _ := ioutil.ReadAll(zr); string(b) != "<h1>Acme</h1>" {
+		t.Fatalf("incorrect upload: %q", b)
+	}
+
+	config := version["config"].(map[string]interface{})
+	b, _ := json.Marshal(config)
+	expected := `{"cleanUrls":true,"headers":[{"glob":"**/*.css","headers":{"Cache-Control":"max-age=3600"}}],` +
+		`"redirects":[{"glob":"/old","location":"/","statusCode":301}],"rewrites":[{"function":"api","glob":"/api/**"}]}`
+	if string(b) != expected {
+		t.Fatalf("incorrect config: %s", b)
+	}
+
+	// Changing the contents of a file replaces the version
+	diff, err := r.Diff(d.State(), testResourceConfig(t, raw), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("unexpected diff: %#v", diff)
+	}
+	ioutil.WriteFile(filepath.Join(public, "css", "main.css"), []byte("h1 { color: red }"), 0600)
+	diff, err = r.Diff(d.State(), testResourceConfig(t, raw), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatalf("expected new version for changed files: %#v", diff)
+	}
+
+	rel := resourceFirebaseHostingRelease()
+	rd := schema.TestResourceDataRaw(t, rel.Schema, map[string]interface{}{
+		"site_id":      "acme-marketing",
+		"channel_id":   "preview",
+		"version_name": d.Id(),
+		"message":      "Launch",
+	})
+	if err := rel.Create(rd, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.HasPrefix(rd.Id(), fmt.Sprintf("projects/%s/sites/acme-marketing/channels/preview/releases/", testProjectID)) {
+		t.Fatalf("incorrect release: %s", rd.Id())
+	}
+	if fake.resources[rd.Id()]["version"].(map[string]interface{})["name"] != d.Id() {
+		t.Fatalf("incorrect released version")
+	}
+	if rd.Get("release_time") != "2026-10-19T00:00:00Z" {
+		t.Fatalf("incorrect release_time: %s", rd.Get("release_time"))
+	}
+}
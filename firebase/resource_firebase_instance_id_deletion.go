@@ -0,0 +1,149 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"firebase.google.com/go/iid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceFirebaseInstanceIDDeletion() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseInstanceIDDeletionCreate,
+		Read:   resourceFirebaseInstanceIDDeletionRead,
+		Update: resourceFirebaseInstanceIDDeletionUpdate,
+		Delete: resourceFirebaseInstanceIDDeletionDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"instance_ids": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+				Set: schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceFirebaseInstanceIDDeletionCreate(d *schema.ResourceData, meta interface{}) error {
+	ids := d.Get("instance_ids").(*schema.Set)
+	log.Printf("[INFO] Deleting %d instance IDs", ids.Len())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resource.UniqueId())
+
+	failed, err := deleteInstanceIDs(client.InstanceID, ids, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		d.Set("instance_ids", ids.Difference(failed))
+		return err
+	}
+
+	return resourceFirebaseInstanceIDDeletionRead(d, meta)
+}
+
+func resourceFirebaseInstanceIDDeletionRead(d *schema.ResourceData, meta interface{}) error {
+	// Deleted instance IDs cannot be read back, the state records which
+	// IDs this resource has purged.
+	return nil
+}
+
+func resourceFirebaseInstanceIDDeletionUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("instance_ids") {
+		o, n := d.GetChange("instance_ids")
+		oldIDs := o.(*schema.Set)
+		newIDs := n.(*schema.Set)
+		added := newIDs.Difference(oldIDs)
+		log.Printf("[INFO] Deleting %d instance IDs", added.Len())
+
+		client, err := projectClient(d, meta)
+		if err != nil {
+			return err
+		}
+
+		failed, err := deleteInstanceIDs(client.InstanceID, added, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			d.Set("instance_ids", newIDs.Difference(failed))
+			return err
+		}
+	}
+
+	return resourceFirebaseInstanceIDDeletionRead(d, meta)
+}
+
+func resourceFirebaseInstanceIDDeletionDelete(d *schema.ResourceData, meta interface{}) error {
+	// Instance IDs cannot be restored, so there is nothing to undo
+	log.Printf("[INFO] Forgetting instance ID deletion: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// deleteInstanceIDs deletes each instance ID, retrying throttled and
+// unavailable responses until timeout. IDs that are already gone count as
+// deleted. It returns the IDs that could not be deleted.
+func deleteInstanceIDs(client *iid.Client, ids *schema.Set, timeout time.Duration) (*schema.Set, error) {
+	failed := schema.NewSet(schema.HashString, nil)
+	var errs *multierror.Error
+
+	for _, v := range ids.List() {
+		id := v.(string)
+
+		err := resource.Retry(timeout, func() *resource.RetryError {
+			log.Printf("[DEBUG] Deleting instance ID: %s", id)
+			err := client.DeleteInstanceID(context.Background(), id)
+			switch {
+			case err == nil:
+				return nil
+			case iid.IsNotFound(err) || iid.IsAlreadyDeleted(err):
+				log.Printf("[DEBUG] Instance ID (%s) is already deleted", id)
+				return nil
+			case iid.IsTooManyRequests(err) || iid.IsServerUnavailable(err) || iid.IsInternal(err):
+				return resource.RetryableError(err)
+			default:
+				return resource.NonRetryableError(err)
+			}
+		})
+		if err != nil {
+			failed.Add(id)
+			errs = multierror.Append(errs, instanceIDError(id, err))
+		}
+	}
+
+	return failed, errs.ErrorOrNil()
+}
+
+// instanceIDError explains how to resolve the iid error codes.
+func instanceIDError(id string, err error) error {
+	var hint string
+	switch {
+	case iid.IsInvalidArgument(err):
+		hint = "the value is not a valid instance ID; registration tokens cannot be deleted this way"
+	case iid.IsInsufficientPermission(err):
+		hint = "the instance ID belongs to another project or the service account lacks permission to delete it"
+	case iid.IsTooManyRequests(err):
+		hint = "the quota for instance ID deletions is exhausted; apply again later or delete fewer IDs at once"
+	case iid.IsServerUnavailable(err) || iid.IsInternal(err):
+		hint = "the instance ID service is unavailable; apply again later"
+	default:
+		return fmt.Errorf("Error deleting instance ID (%s): %s", id, err)
+	}
+	return fmt.Errorf("Error deleting instance ID (%s): %s: %s", id, hint, err)
+}
@@ -0,0 +1,80 @@
+package firebase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testInstanceIDServer fakes the instance ID deletion endpoint. The status
+// returned for an ID is taken from statuses, defaulting to 200.
+type testInstanceIDServer struct {
+	sync.Mutex
+	statuses map[string]int
+	deleted  []string
+}
+
+func (s *testInstanceIDServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	prefix := fmt.Sprintf("/v1/project/%s/instanceId/", testProjectID)
+	id := strings.TrimPrefix(r.URL.Path, prefix)
+
+	s.Lock()
+	defer s.Unlock()
+	if status, ok := s.statuses[id]; ok {
+		w.WriteHeader(status)
+		return
+	}
+	s.deleted = append(s.deleted, id)
+}
+
+func TestResourceFirebaseInstanceIDDeletion(t *testing.T) {
+	fake := &testInstanceIDServer{
+		statuses: map[string]int{
+			"gone":    http.StatusNotFound,
+			"deleted": http.StatusConflict,
+			"token":   http.StatusBadRequest,
+		},
+	}
+	mux := http.NewServeMux()
+	mux.Handle(fmt.Sprintf("/v1/project/%s/instanceId/", testProjectID), fake)
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		"https://console.firebase.google.com": srv.URL,
+	})
+
+	r := resourceFirebaseInstanceIDDeletion()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"instance_ids": []interface{}{"device-1", "gone", "deleted"},
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(fake.deleted) != "[device-1]" {
+		t.Fatalf("unexpected deletions: %v", fake.deleted)
+	}
+	if d.Get("instance_ids").(*schema.Set).Len() != 3 {
+		t.Fatalf("already deleted IDs were dropped from state")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"instance_ids": []interface{}{"device-2", "token"},
+	})
+	err := r.Create(d, meta)
+	if err == nil || !strings.Contains(err.Error(), "registration tokens") {
+		t.Fatalf("expected invalid instance ID error, got: %v", err)
+	}
+	ids := d.Get("instance_ids").(*schema.Set)
+	if ids.Contains("token") || !ids.Contains("device-2") {
+		t.Fatalf("unexpected instance_ids in state: %v", ids.List())
+	}
+}
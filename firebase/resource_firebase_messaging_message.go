@@ -0,0 +1,547 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"firebase.google.com/go/messaging"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// messagingMessageKeys are the attributes that make up the message payload.
+var messagingMessageKeys = []string{
+	"token", "topic", "condition", "data", "notification", "android", "apns", "webpush",
+}
+
+func resourceFirebaseMessagingMessage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseMessagingMessageCreate,
+		Read:   resourceFirebaseMessagingMessageRead,
+		Update: resourceFirebaseMessagingMessageUpdate,
+		Delete: resourceFirebaseMessagingMessageDelete,
+
+		CustomizeDiff: resourceFirebaseMessagingMessageCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"topic", "condition"},
+			},
+			"topic": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateTopicName,
+				ConflictsWith: []string{"token", "condition"},
+			},
+			"condition": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"token", "topic"},
+			},
+			"data": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"notification": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"title": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"body": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"android": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"collapse_key": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"priority": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"normal", "high"}, false),
+						},
+						"ttl": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"restricted_package_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"data": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+						"notification": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"title": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"body": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"icon": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"color": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"sound": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"tag": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"click_action": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"body_loc_key": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"body_loc_args": {
+										Type:     schema.TypeList,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"title_loc_key": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"title_loc_args": {
+										Type:     schema.TypeList,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"apns": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"headers": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+						"aps": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"alert_string": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"alert": {
+										Type:     schema.TypeList,
+										Optional: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"title": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"body": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"loc_key": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"loc_args": {
+													Type:     schema.TypeList,
+													Optional: true,
+													Elem:     &schema.Schema{Type: schema.TypeString},
+												},
+												"title_loc_key": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"title_loc_args": {
+													Type:     schema.TypeList,
+													Optional: true,
+													Elem:     &schema.Schema{Type: schema.TypeString},
+												},
+												"action_loc_key": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"launch_image": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+											},
+										},
+									},
+									"badge": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Default:      -1,
+										ValidateFunc: validation.IntAtLeast(-1),
+									},
+									"sound": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"content_available": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+									"mutable_content": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+									"category": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"thread_id": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"custom_data": {
+										Type:     schema.TypeMap,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"custom_data": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"webpush": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"headers": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+						"data": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+						"notification": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"title": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"body": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"icon": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"message_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseMessagingMessageCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := messagingClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	message, err := expandMessagingMessage(d.Get)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Sending message")
+
+	id, err := client.Send(context.Background(), message)
+	if err != nil {
+		return fmt.Errorf("Error sending message: %s", err)
+	}
+	log.Printf("[INFO] Message ID: %s", id)
+
+	d.SetId(id)
+	d.Set("message_id", id)
+
+	return resourceFirebaseMessagingMessageRead(d, meta)
+}
+
+func resourceFirebaseMessagingMessageRead(d *schema.ResourceData, meta interface{}) error {
+	// Sent messages cannot be read back from FCM
+	return nil
+}
+
+func resourceFirebaseMessagingMessageUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Messages are only sent again when triggers change, which forces a new
+	// resource. Other changes are recorded without sending anything.
+	return resourceFirebaseMessagingMessageRead(d, meta)
+}
+
+func resourceFirebaseMessagingMessageDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Forgetting message: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// resourceFirebaseMessagingMessageCustomizeDiff validates the payload with
+// FCM in dry-run mode whenever the message or its triggers change.
+func resourceFirebaseMessagingMessageCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	changed := d.Id() == "" || d.HasChange("triggers")
+	for _, k := range messagingMessageKeys {
+		if !d.NewValueKnown(k) {
+			log.Printf("[DEBUG] Skipping dry run, %s is not known yet", k)
+			return nil
+		}
+		changed = changed || d.HasChange(k)
+	}
+	if !changed {
+		return nil
+	}
+
+	message, err := expandMessagingMessage(d.Get)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Validating message with a dry run")
+
+	client, err := meta.(Client).forProject(d.Get("project").(string))
+	if err != nil {
+		return err
+	}
+	_, err = client.Messaging.SendDryRun(context.Background(), message)
+	if err != nil {
+		return fmt.Errorf("Error validating message: %s", err)
+	}
+	return nil
+}
+
+func expandMessagingMessage(get func(string) interface{}) (*messaging.Message, error) {
+	message := &messaging.Message{
+		Token:     get("token").(string),
+		Topic:     get("topic").(string),
+		Condition: get("condition").(string),
+		Data:      expandStringMap(get("data")),
+	}
+
+	if v := get("notification").([]interface{}); len(v) > 0 && v[0] != nil {
+		n := v[0].(map[string]interface{})
+		message.Notification = &messaging.Notification{
+			Title: n["title"].(string),
+			Body:  n["body"].(string),
+		}
+	}
+
+	if v := get("android").([]interface{}); len(v) > 0 && v[0] != nil {
+		android, err := expandAndroidConfig(v[0].(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		message.Android = android
+	}
+
+	if v := get("apns").([]interface{}); len(v) > 0 && v[0] != nil {
+		message.APNS = expandAPNSConfig(v[0].(map[string]interface{}))
+	}
+
+	if v := get("webpush").([]interface{}); len(v) > 0 && v[0] != nil {
+		message.Webpush = expandWebpushConfig(v[0].(map[string]interface{}))
+	}
+
+	return message, nil
+}
+
+func expandAndroidConfig(a map[string]interface{}) (*messaging.AndroidConfig, error) {
+	config := &messaging.AndroidConfig{
+		CollapseKey:           a["collapse_key"].(string),
+		Priority:              a["priority"].(string),
+		RestrictedPackageName: a["restricted_package_name"].(string),
+		Data:                  expandStringMap(a["data"]),
+	}
+
+	if v := a["ttl"].(string); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		config.TTL = &ttl
+	}
+
+	if v := a["notification"].([]interface{}); len(v) > 0 && v[0] != nil {
+		n := v[0].(map[string]interface{})
+		config.Notification = &messaging.AndroidNotification{
+			Title:        n["title"].(string),
+			Body:         n["body"].(string),
+			Icon:         n["icon"].(string),
+			Color:        n["color"].(string),
+			Sound:        n["sound"].(string),
+			Tag:          n["tag"].(string),
+			ClickAction:  n["click_action"].(string),
+			BodyLocKey:   n["body_loc_key"].(string),
+			BodyLocArgs:  expandStringList(n["body_loc_args"]),
+			TitleLocKey:  n["title_loc_key"].(string),
+			TitleLocArgs: expandStringList(n["title_loc_args"]),
+		}
+	}
+
+	return config, nil
+}
+
+func expandAPNSConfig(a map[string]interface{}) *messaging.APNSConfig {
+	config := &messaging.APNSConfig{
+		Headers: expandStringMap(a["headers"]),
+	}
+
+	payload := &messaging.APNSPayload{
+		CustomData: expandInterfaceMap(a["custom_data"]),
+	}
+	if v := a["aps"].([]interface{}); len(v) > 0 && v[0] != nil {
+		aps := v[0].(map[string]interface{})
+		payload.Aps = &messaging.Aps{
+			AlertString:      aps["alert_string"].(string),
+			Sound:            aps["sound"].(string),
+			ContentAvailable: aps["content_available"].(bool),
+			MutableContent:   aps["mutable_content"].(bool),
+			Category:         aps["category"].(string),
+			ThreadID:         aps["thread_id"].(string),
+			CustomData:       expandInterfaceMap(aps["custom_data"]),
+		}
+		if badge := aps["badge"].(int); badge >= 0 {
+			payload.Aps.Badge = &badge
+		}
+		if v := aps["alert"].([]interface{}); len(v) > 0 && v[0] != nil {
+			alert := v[0].(map[string]interface{})
+			payload.Aps.Alert = &messaging.ApsAlert{
+				Title:        alert["title"].(string),
+				Body:         alert["body"].(string),
+				LocKey:       alert["loc_key"].(string),
+				LocArgs:      expandStringList(alert["loc_args"]),
+				TitleLocKey:  alert["title_loc_key"].(string),
+				TitleLocArgs: expandStringList(alert["title_loc_args"]),
+				ActionLocKey: alert["action_loc_key"].(string),
+				LaunchImage:  alert["launch_image"].(string),
+			}
+		}
+	}
+	if payload.Aps != nil || len(payload.CustomData) > 0 {
+		if payload.Aps == nil {
+			payload.Aps = &messaging.Aps{}
+		}
+		config.Payload = payload
+	}
+
+	return config
+}
+
+func expandWebpushConfig(w map[string]interface{}) *messaging.WebpushConfig {
+	config := &messaging.WebpushConfig{
+		Headers: expandStringMap(w["headers"]),
+		Data:    expandStringMap(w["data"]),
+	}
+
+	if v := w["notification"].([]interface{}); len(v) > 0 && v[0] != nil {
+		n := v[0].(map[string]interface{})
+		config.Notification = &messaging.WebpushNotification{
+			Title: n["title"].(string),
+			Body:  n["body"].(string),
+			Icon:  n["icon"].(string),
+		}
+	}
+
+	return config
+}
+
+func expandStringMap(v interface{}) map[string]string {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+func expandInterfaceMap(v interface{}) map[string]interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func expandStringList(v interface{}) []string {
+	l, ok := v.([]interface{})
+	if !ok || len(l) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(l))
+	for _, v := range l {
+		result = append(result, v.(string))
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testFCMServer fakes the FCM v1 send endpoint. Messages titled "invalid"
+// are rejected with INVALID_ARGUMENT.
+type testFCMServer struct {
+	sent    []map[string]interface{}
+	dryRuns []map[string]interface{}
+}
+
+func (s *testFCMServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ValidateOnly bool                   `json:"validate_only"`
+		Message      map[string]interface{} `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if n, ok := req.Message["notification"].(map[string]interface{}); ok && n["title"] == "invalid" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"status":"INVALID_ARGUMENT","message":"invalid notification"}}`)
+		return
+	}
+
+	if req.ValidateOnly {
+		s.dryRuns = append(s.dryRuns, req.Message)
+		fmt.Fprintf(w, `{"name":"projects/%s/messages/fake_message_id"}`, testProjectID)
+		return
+	}
+	s.sent = append(s.sent, req.Message)
+	fmt.Fprintf(w, `{"name":"projects/%s/messages/%d"}`, testProjectID, len(s.sent))
+}
+
+func TestResourceFirebaseMessagingMessage(t *testing.T) {
+	fake := &testFCMServer{}
+	mux := http.NewServeMux()
+	mux.Handle(fmt.Sprintf("/v1/projects/%s/messages:send", testProjectID), fake)
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		"https://fcm.googleapis.com": srv.URL,
+	})
+
+	raw := func(title string) map[string]interface{} {
+		return map[string]interface{}{
+			"topic": "/topics/maintenance",
+			"data":  map[string]interface{}{"window": "2018-06-01T02:00:00Z"},
+			"notification": []interface{}{
+				map[string]interface{}{"title": title, "body": "Back in 30 minutes"},
+			},
+			"android": []interface{}{
+				map[string]interface{}{"priority": "high", "ttl": "1h"},
+			},
+			"apns": []interface{}{
+				map[string]interface{}{
+					"headers": map[string]interface{}{"apns-priority": "10"},
+					"aps": []interface{}{
+						map[string]interface{}{
+							"badge": 0,
+							"alert": []interface{}{
+								map[string]interface{}{"title": title},
+							},
+						},
+					},
+				},
+			},
+			"triggers": map[string]interface{}{"release": "1.2.3"},
+		}
+	}
+
+	r := resourceFirebaseMessagingMessage()
+	diff := func(raw map[string]interface{}) error {
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		_, err = r.Diff(nil, terraform.NewResourceConfig(c), meta)
+		return err
+	}
+
+	if err := diff(raw("Maintenance")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.dryRuns) == 0 || len(fake.sent) != 0 {
+		t.Fatalf("expected only dry runs, got %d dry runs and %d sends", len(fake.dryRuns), len(fake.sent))
+	}
+	if fake.dryRuns[0]["topic"] != "maintenance" {
+		t.Fatalf("incorrect topic: %#v", fake.dryRuns[0]["topic"])
+	}
+	aps := fake.dryRuns[0]["apns"].(map[string]interface{})["payload"].(map[string]interface{})["aps"].(map[string]interface{})
+	if aps["badge"] != float64(0) {
+		t.Fatalf("incorrect badge: %#v", aps["badge"])
+	}
+
+	if err := diff(raw("invalid")); err == nil || !strings.Contains(err.Error(), "invalid notification") {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw("Maintenance"))
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.sent) != 1 {
+		t.Fatalf("expected a single send, got %d", len(fake.sent))
+	}
+	if id := d.Get("message_id").(string); id != fmt.Sprintf("projects/%s/messages/1", testProjectID) {
+		t.Fatalf("incorrect message_id: %q", id)
+	}
+	android := fake.sent[0]["android"].(map[string]interface{})
+	if android["ttl"] != "3600s" || android["priority"] != "high" {
+		t.Fatalf("incorrect android config: %#v", android)
+	}
+}
@@ -0,0 +1,177 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"firebase.google.com/go/messaging"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// maxTopicManagementTokens is the number of registration tokens accepted by
+// a single SubscribeToTopic or UnsubscribeFromTopic call.
+const maxTopicManagementTokens = 1000
+
+func resourceFirebaseMessagingTopicSubscription() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseMessagingTopicSubscriptionCreate,
+		Read:   resourceFirebaseMessagingTopicSubscriptionRead,
+		Update: resourceFirebaseMessagingTopicSubscriptionUpdate,
+		Delete: resourceFirebaseMessagingTopicSubscriptionDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"topic": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTopicName,
+			},
+			"tokens": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+				Set: schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceFirebaseMessagingTopicSubscriptionCreate(d *schema.ResourceData, meta interface{}) error {
+	topic := d.Get("topic").(string)
+	log.Printf("[INFO] Creating subscriptions to topic: %s", topic)
+
+	client, err := messagingClient(d, meta)
+	if err != nil {
+		return err
+	}
+	tokens := d.Get("tokens").(*schema.Set)
+
+	d.SetId(topic)
+
+	failed, err := manageTopicSubscriptions(client.SubscribeToTopic, topic, expandStringSet(tokens))
+	if err != nil {
+		d.Set("tokens", tokens.Difference(failed))
+		return fmt.Errorf("Error subscribing to topic (%s): %s", topic, err)
+	}
+
+	return resourceFirebaseMessagingTopicSubscriptionRead(d, meta)
+}
+
+func resourceFirebaseMessagingTopicSubscriptionRead(d *schema.ResourceData, meta interface{}) error {
+	// FCM does not offer a way to list the subscribers of a topic, so the
+	// tokens in state are the ones this resource subscribed.
+	return nil
+}
+
+func resourceFirebaseMessagingTopicSubscriptionUpdate(d *schema.ResourceData, meta interface{}) error {
+	topic := d.Get("topic").(string)
+	log.Printf("[INFO] Updating subscriptions to topic: %s", topic)
+
+	client, err := messagingClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("tokens") {
+		o, n := d.GetChange("tokens")
+		oldTokens := o.(*schema.Set)
+		newTokens := n.(*schema.Set)
+		removed := oldTokens.Difference(newTokens)
+		added := newTokens.Difference(oldTokens)
+
+		failed, err := manageTopicSubscriptions(client.UnsubscribeFromTopic, topic, expandStringSet(removed))
+		if err != nil {
+			// Tokens that are still subscribed stay in state
+			d.Set("tokens", oldTokens.Difference(removed.Difference(failed)))
+			return fmt.Errorf("Error unsubscribing from topic (%s): %s", topic, err)
+		}
+
+		failed, err = manageTopicSubscriptions(client.SubscribeToTopic, topic, expandStringSet(added))
+		if err != nil {
+			d.Set("tokens", newTokens.Difference(failed))
+			return fmt.Errorf("Error subscribing to topic (%s): %s", topic, err)
+		}
+	}
+
+	return resourceFirebaseMessagingTopicSubscriptionRead(d, meta)
+}
+
+func resourceFirebaseMessagingTopicSubscriptionDelete(d *schema.ResourceData, meta interface{}) error {
+	topic := d.Get("topic").(string)
+	log.Printf("[INFO] Deleting subscriptions to topic: %s", topic)
+
+	client, err := messagingClient(d, meta)
+	if err != nil {
+		return err
+	}
+	tokens := d.Get("tokens").(*schema.Set)
+
+	failed, err := manageTopicSubscriptions(client.UnsubscribeFromTopic, topic, expandStringSet(tokens))
+	if err != nil {
+		d.Set("tokens", failed)
+		return fmt.Errorf("Error unsubscribing from topic (%s): %s", topic, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+type topicManagementFunc func(context.Context, []string, string) (*messaging.TopicManagementResponse, error)
+
+// manageTopicSubscriptions applies f to tokens in batches of
+// maxTopicManagementTokens. It returns the tokens that could not be handled,
+// along with an error naming each of them and the reason reported by FCM.
+func manageTopicSubscriptions(f topicManagementFunc, topic string, tokens []string) (*schema.Set, error) {
+	failed := schema.NewSet(schema.HashString, nil)
+	var reasons []string
+
+	for _, batch := range chunkStrings(tokens, maxTopicManagementTokens) {
+		log.Printf("[DEBUG] Managing %d subscriptions to topic: %s", len(batch), topic)
+
+		resp, err := f(context.Background(), batch, topic)
+		if err != nil {
+			for _, token := range batch {
+				failed.Add(token)
+			}
+			reasons = append(reasons, fmt.Sprintf("%d tokens: %s", len(batch), err))
+			continue
+		}
+		for _, e := range resp.Errors {
+			failed.Add(batch[e.Index])
+			reasons = append(reasons, fmt.Sprintf("%s: %s", batch[e.Index], e.Reason))
+		}
+	}
+
+	if len(reasons) > 0 {
+		return failed, fmt.Errorf("%d of %d tokens failed:\n\t%s",
+			failed.Len(), len(tokens), strings.Join(reasons, "\n\t"))
+	}
+	return failed, nil
+}
+
+func chunkStrings(s []string, size int) [][]string {
+	var chunks [][]string
+	for len(s) > size {
+		chunks = append(chunks, s[:size])
+		s = s[size:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+func expandStringSet(s *schema.Set) []string {
+	result := make([]string, 0, s.Len())
+	for _, v := range s.List() {
+		result = append(result, v.(string))
+	}
+	return result
+}
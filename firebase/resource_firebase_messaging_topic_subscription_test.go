@@ -0,0 +1,104 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testTopicServer fakes the IID batchAdd and batchRemove endpoints. Tokens
+// prefixed with "invalid-" are rejected individually.
+type testTopicServer struct {
+	sync.Mutex
+	batches     []int
+	subscribers map[string]bool
+}
+
+func (s *testTopicServer) handler(subscribe bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Topic  string   `json:"to"`
+			Tokens []string `json:"registration_tokens"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.Lock()
+		defer s.Unlock()
+		s.batches = append(s.batches, len(req.Tokens))
+
+		var results []map[string]interface{}
+		for _, token := range req.Tokens {
+			if strings.HasPrefix(token, "invalid-") {
+				results = append(results, map[string]interface{}{"error": "INVALID_ARGUMENT"})
+				continue
+			}
+			if subscribe {
+				s.subscribers[token] = true
+			} else {
+				delete(s.subscribers, token)
+			}
+			results = append(results, map[string]interface{}{})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}
+}
+
+func TestResourceFirebaseMessagingTopicSubscription(t *testing.T) {
+	fake := &testTopicServer{subscribers: make(map[string]bool)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/iid/v1:batchAdd", fake.handler(true))
+	mux.HandleFunc("/iid/v1:batchRemove", fake.handler(false))
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		"https://iid.googleapis.com": srv.URL,
+	})
+
+	var tokens []interface{}
+	for i := 0; i < 2500; i++ {
+		tokens = append(tokens, fmt.Sprintf("token-%d", i))
+	}
+
+	r := resourceFirebaseMessagingTopicSubscription()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"topic":  "/topics/kiosks",
+		"tokens": tokens,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(fake.subscribers) != 2500 {
+		t.Fatalf("expected 2500 subscribers, got %d", len(fake.subscribers))
+	}
+	if fmt.Sprint(fake.batches) != "[1000 1000 500]" {
+		t.Fatalf("unexpected batches: %v", fake.batches)
+	}
+
+	fake.batches = nil
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"topic":  "/topics/kiosks",
+		"tokens": []interface{}{"token-1", "token-2", "invalid-1"},
+	})
+	if err := r.Create(d, meta); err == nil || !strings.Contains(err.Error(), "invalid-1") {
+		t.Fatalf("expected error naming invalid-1, got: %v", err)
+	}
+	if d.Get("tokens").(*schema.Set).Contains("invalid-1") {
+		t.Fatalf("failed token was kept in state")
+	}
+
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.subscribers["token-1"] || fake.subscribers["token-2"] {
+		t.Fatalf("tokens were not unsubscribed")
+	}
+}
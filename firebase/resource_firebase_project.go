@@ -0,0 +1,146 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type firebaseProject struct {
+	ProjectID     string `json:"projectId"`
+	ProjectNumber string `json:"projectNumber"`
+	DisplayName   string `json:"displayName"`
+	State         string `json:"state"`
+	Resources     struct {
+		HostingSite              string `json:"hostingSite"`
+		RealtimeDatabaseInstance string `json:"realtimeDatabaseInstance"`
+		StorageBucket            string `json:"storageBucket"`
+		LocationID               string `json:"locationId"`
+	} `json:"resources"`
+}
+
+func resourceFirebaseProject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseProjectCreate,
+		Read:   resourceFirebaseProjectRead,
+		Delete: resourceFirebaseProjectDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseProjectImportState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"project_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"hosting_site": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"realtime_database_instance": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"storage_bucket": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"location_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseProjectCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Adding Firebase to project: %s", client.ProjectID)
+
+	var op operation
+	url := fmt.Sprintf("%s/projects/%s:addFirebase", firebaseManagementEndpoint, client.ProjectID)
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, struct{}{}, &op)
+	if err != nil {
+		return fmt.Errorf("Error adding Firebase to project (%s): %s", client.ProjectID, err)
+	}
+
+	err = waitForOperation(client.HTTP, firebaseManagementEndpoint, &op, d.Timeout(schema.TimeoutCreate), nil)
+	if err != nil {
+		return fmt.Errorf("Error adding Firebase to project (%s): %s", client.ProjectID, err)
+	}
+
+	d.SetId(client.ProjectID)
+
+	return resourceFirebaseProjectRead(d, meta)
+}
+
+func resourceFirebaseProjectRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Firebase project: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/projects/%s", firebaseManagementEndpoint, d.Id())
+
+	var project firebaseProject
+	err = sendRequest(context.Background(), client.HTTP, "GET", url, nil, &project)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Firebase project (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading Firebase project (%s): %s", d.Id(), err)
+	}
+	if project.State == "DELETED" {
+		log.Printf("[WARN] Firebase project (%s) is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("project_number", project.ProjectNumber)
+	d.Set("display_name", project.DisplayName)
+	d.Set("hosting_site", project.Resources.HostingSite)
+	d.Set("realtime_database_instance", project.Resources.RealtimeDatabaseInstance)
+	d.Set("storage_bucket", project.Resources.StorageBucket)
+	d.Set("location_id", project.Resources.LocationID)
+
+	return nil
+}
+
+func resourceFirebaseProjectDelete(d *schema.ResourceData, meta interface{}) error {
+	// Firebase cannot be removed from a project, only the project itself
+	// can be deleted
+	log.Printf("[WARN] Firebase stays enabled on project %s, removing from state only", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// resourceFirebaseProjectImportState accepts a project ID or
+// projects/<project>.
+func resourceFirebaseProjectImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := strings.TrimPrefix(d.Id(), "projects/")
+	if id == "" || strings.Contains(id, "/") {
+		return nil, fmt.Errorf("Import ID %q should be a project ID or projects/<project>", d.Id())
+	}
+	d.SetId(id)
+	d.Set("project", id)
+	return []*schema.ResourceData{d}, nil
+}
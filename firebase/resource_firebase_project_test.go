@@ -0,0 +1,135 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testManagementServer fakes the Firebase Management API projects and their
+// long-running operations, which are done on their second poll.
+type testManagementServer struct {
+	sync.Mutex
+	projects   map[string]map[string]interface{}
+	operations map[string]int
+}
+
+func (s *testManagementServer) handleProjects(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	project := strings.TrimPrefix(r.URL.Path, "/v1beta1/projects/")
+	if r.Method == "POST" {
+		if !strings.HasSuffix(project, ":addFirebase") {
+			http.NotFound(w, r)
+			return
+		}
+		project = strings.TrimSuffix(project, ":addFirebase")
+		if s.projects[project] != nil {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error":{"code":409,"message":"ALREADY_EXISTS"}}`)
+			return
+		}
+		s.projects[project] = map[string]interface{}{
+			"projectId":     project,
+			"projectNumber": "123456789012",
+			"displayName":   "Mock Project",
+			"state":         "ACTIVE",
+			"resources": map[string]interface{}{
+				"hostingSite":              project,
+				"realtimeDatabaseInstance": project + "-default-rtdb",
+				"storageBucket":            project + ".appspot.com",
+				"locationId":               "us-central",
+			},
+		}
+		name := "operations/add-" + project
+		s.operations[name] = 0
+		fmt.Fprintf(w, `{"name":%q}`, name)
+		return
+	}
+
+	if s.projects[project] == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":404,"message":"NOT_FOUND"}}`)
+		return
+	}
+	json.NewEncoder(w).Encode(s.projects[project])
+}
+
+func (s *testManagementServer) handleOperations(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1beta1/")
+	polls, ok := s.operations[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.operations[name] = polls + 1
+	fmt.Fprintf(w, `{"name":%q,"done":%t}`, name, polls > 0)
+}
+
+func TestResourceFirebaseProject(t *testing.T) {
+	fake := &testManagementServer{
+		projects:   make(map[string]map[string]interface{}),
+		operations: make(map[string]int),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1beta1/projects/", fake.handleProjects)
+	mux.HandleFunc("/v1beta1/operations/", fake.handleOperations)
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		firebaseManagementEndpoint: srv.URL + "/v1beta1",
+	})
+
+	r := resourceFirebaseProject()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"project": "other-project",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "other-project" {
+		t.Fatalf("incorrect ID: %q", d.Id())
+	}
+	if fake.operations["operations/add-other-project"] != 2 {
+		t.Fatalf("operation was not polled until done")
+	}
+	if d.Get("project_number") != "123456789012" || d.Get("location_id") != "us-central" {
+		t.Fatalf("incorrect project: %s %s", d.Get("project_number"), d.Get("location_id"))
+	}
+	if d.Get("storage_bucket") != "other-project.appspot.com" {
+		t.Fatalf("incorrect storage_bucket: %s", d.Get("storage_bucket"))
+	}
+
+	if err := r.Create(d, meta); err == nil {
+		t.Fatalf("expected error adding Firebase twice")
+	}
+
+	for _, id := range []string{"other-project", "projects/other-project"} {
+		states, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: id}), meta)
+		if err != nil {
+			t.Fatalf("%s: err: %s", id, err)
+		}
+		if states[0].Id() != "other-project" || states[0].Get("project") != "other-project" {
+			t.Fatalf("%s: incorrect import: %s", id, states[0].Id())
+		}
+	}
+
+	delete(fake.projects, "other-project")
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("missing project was kept in state")
+	}
+}
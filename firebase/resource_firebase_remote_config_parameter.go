@@ -0,0 +1,344 @@
+package firebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceFirebaseRemoteConfigParameter() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseRemoteConfigParameterCreate,
+		Read:   resourceFirebaseRemoteConfigParameterRead,
+		Update: resourceFirebaseRemoteConfigParameterUpdate,
+		Delete: resourceFirebaseRemoteConfigParameterDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseRemoteConfigParameterImportState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"default_value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"use_in_app_default": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"conditional_value": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     remoteConfigConditionalValueResource(),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"value_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "STRING",
+				ValidateFunc: validation.StringInSlice([]string{
+					"STRING", "BOOLEAN", "NUMBER", "JSON",
+				}, false),
+			},
+			"group": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// The group created for the parameter, which is removed once its
+			// last parameter is. Groups which existed already are kept.
+			"created_group": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseRemoteConfigParameterCreate(d *schema.ResourceData, meta interface{}) error {
+	key := d.Get("key").(string)
+	log.Printf("[INFO] Creating Remote Config parameter: %s", key)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group, created := d.Get("group").(string), ""
+	err = updateRemoteConfigTemplate(client, d.Timeout(schema.TimeoutCreate), func(t map[string]interface{}) error {
+		if hasRemoteConfigParameter(t, key) {
+			return fmt.Errorf("Remote Config parameter %q already exists, import it to manage it", key)
+		}
+		ok, err := setRemoteConfigParameter(t, key, group, expandRemoteConfigParameterData(d))
+		created = ""
+		if ok {
+			created = group
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating Remote Config parameter (%s): %s", key, err)
+	}
+
+	d.SetId(key)
+	d.Set("created_group", created)
+
+	return resourceFirebaseRemoteConfigParameterRead(d, meta)
+}
+
+func resourceFirebaseRemoteConfigParameterRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Remote Config parameter: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var template remoteConfigTemplate
+	err = sendRequest(context.Background(), client.HTTP, "GET", remoteConfigURL(client.ProjectID), nil, &template)
+	if err != nil {
+		return fmt.Errorf("Error reading Remote Config parameter (%s): %s", d.Id(), err)
+	}
+
+	group, p := "", template.Parameters[d.Id()]
+	for name, g := range template.ParameterGroups {
+		if p != nil {
+			break
+		}
+		group, p = name, g.Parameters[d.Id()]
+	}
+	if p == nil {
+		log.Printf("[WARN] Remote Config parameter (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	for k, v := range flattenRemoteConfigParameter(d.Id(), group, p) {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("Error setting %s: %s", k, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceFirebaseRemoteConfigParameterUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Updating Remote Config parameter: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	group, previous := d.Get("group").(string), d.Get("created_group").(string)
+	created := previous
+	err = updateRemoteConfigTemplate(client, d.Timeout(schema.TimeoutUpdate), func(t map[string]interface{}) error {
+		// The created group is kept as long as the parameter stays in it
+		if previous != group {
+			removeRemoteConfigParameter(t, d.Id(), previous)
+		}
+		ok, err := setRemoteConfigParameter(t, d.Id(), group, expandRemoteConfigParameterData(d))
+		switch {
+		case ok:
+			created = group
+		case previous != group:
+			created = ""
+		default:
+			created = previous
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Error updating Remote Config parameter (%s): %s", d.Id(), err)
+	}
+	d.Set("created_group", created)
+
+	return resourceFirebaseRemoteConfigParameterRead(d, meta)
+}
+
+func resourceFirebaseRemoteConfigParameterDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Deleting Remote Config parameter: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = updateRemoteConfigTemplate(client, d.Timeout(schema.TimeoutDelete), func(t map[string]interface{}) error {
+		removeRemoteConfigParameter(t, d.Id(), d.Get("created_group").(string))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error deleting Remote Config parameter (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseRemoteConfigParameterImportState accepts a parameter key
+// or <project>/<key>.
+func resourceFirebaseRemoteConfigParameterImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if parts := strings.SplitN(d.Id(), "/", 2); len(parts) == 2 {
+		d.Set("project", parts[0])
+		d.SetId(parts[1])
+	}
+	d.Set("key", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandRemoteConfigParameterData expands the configured fields of the
+// parameter, other than its key and group.
+func expandRemoteConfigParameterData(d *schema.ResourceData) *remoteConfigParameter {
+	return expandRemoteConfigParameter(map[string]interface{}{
+		"default_value":      d.Get("default_value"),
+		"use_in_app_default": d.Get("use_in_app_default"),
+		"conditional_value":  d.Get("conditional_value"),
+		"description":        d.Get("description"),
+		"value_type":         d.Get("value_type"),
+	})
+}
+
+// updateRemoteConfigTemplate reads the template, modifies it with update and
+// publishes it if anything changed. The template is read again and update
+// retried whenever it was published by someone else in between, so
+// concurrent updates to different parameters are merged. The template is
+// kept as generic JSON so fields this provider does not know are preserved.
+func updateRemoteConfigTemplate(client Client, timeout time.Duration, update func(map[string]interface{}) error) error {
+	url := remoteConfigURL(client.ProjectID)
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		var template map[string]interface{}
+		header, err := sendRequestWithHeader(context.Background(), client.HTTP, "GET", url, nil, nil, &template)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if template == nil {
+			template = make(map[string]interface{})
+		}
+		delete(template, "version")
+
+		before, err := json.Marshal(template)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if err := update(template); err != nil {
+			return resource.NonRetryableError(err)
+		}
+		after, err := json.Marshal(template)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if bytes.Equal(before, after) {
+			log.Printf("[DEBUG] Remote Config template is up to date")
+			return nil
+		}
+
+		_, err = sendRequestWithHeader(context.Background(), client.HTTP, "PUT", url,
+			http.Header{"If-Match": {header.Get("ETag")}}, template, nil)
+		if isPreconditionFailed(err) {
+			log.Printf("[DEBUG] Remote Config template was published concurrently, retrying: %s", err)
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+// hasRemoteConfigParameter reports whether a generic template has the
+// parameter key, in any group.
+func hasRemoteConfigParameter(t map[string]interface{}, key string) bool {
+	if parameters, ok := t["parameters"].(map[string]interface{}); ok && parameters[key] != nil {
+		return true
+	}
+	groups, _ := t["parameterGroups"].(map[string]interface{})
+	for _, g := range groups {
+		if g, ok := g.(map[string]interface{}); ok {
+			if parameters, ok := g["parameters"].(map[string]interface{}); ok && parameters[key] != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeRemoteConfigParameter removes the parameter key from a generic
+// template, wherever it is. The group named created is removed as well when
+// key was its last parameter.
+func removeRemoteConfigParameter(t map[string]interface{}, key, created string) {
+	if parameters, ok := t["parameters"].(map[string]interface{}); ok {
+		delete(parameters, key)
+	}
+	groups, _ := t["parameterGroups"].(map[string]interface{})
+	for name, g := range groups {
+		if g, ok := g.(map[string]interface{}); ok {
+			if parameters, ok := g["parameters"].(map[string]interface{}); ok && parameters[key] != nil {
+				delete(parameters, key)
+				if name == created && len(parameters) == 0 {
+					delete(groups, name)
+				}
+			}
+		}
+	}
+}
+
+// setRemoteConfigParameter replaces the parameter key of a generic template
+// with p, moving it to group. It reports whether group had to be created.
+func setRemoteConfigParameter(t map[string]interface{}, key, group string, p *remoteConfigParameter) (bool, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return false, err
+	}
+	var parameter map[string]interface{}
+	if err := json.Unmarshal(b, &parameter); err != nil {
+		return false, err
+	}
+
+	removeRemoteConfigParameter(t, key, "")
+
+	parent, created := t, false
+	if group != "" {
+		groups, ok := t["parameterGroups"].(map[string]interface{})
+		if !ok {
+			groups = make(map[string]interface{})
+			t["parameterGroups"] = groups
+		}
+		g, ok := groups[group].(map[string]interface{})
+		if !ok {
+			g = make(map[string]interface{})
+			groups[group] = g
+			created = true
+		}
+		parent = g
+	}
+	parameters, ok := parent["parameters"].(map[string]interface{})
+	if !ok {
+		parameters = make(map[string]interface{})
+		parent["parameters"] = parameters
+	}
+	parameters[key] = parameter
+	return created, nil
+}
@@ -0,0 +1,138 @@
+package firebase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceFirebaseRemoteConfigParameter(t *testing.T) {
+	fake := newTestRemoteConfigServer()
+	meta, closeServer := testAPIClient(t, fake, remoteConfigEndpoint)
+	defer closeServer()
+	fake.conflicts = 2
+
+	r := resourceFirebaseRemoteConfigParameter()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"key":           "new_checkout",
+		"default_value": "false",
+		"value_type":    "BOOLEAN",
+		"group":         "checkout",
+		"conditional_value": []interface{}{
+			map[string]interface{}{"condition": "beta_testers", "value": "true"},
+		},
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Concurrent publishes are merged instead of overwritten
+	current := fake.current()
+	if current.Parameters["other_team_flag_0"] == nil || current.Parameters["other_team_flag_1"] == nil {
+		t.Fatalf("concurrently published parameters were overwritten: %v", current.Parameters)
+	}
+	p := current.ParameterGroups["checkout"].Parameters["new_checkout"]
+	if p == nil || *p.DefaultValue.Value != "false" || *p.ConditionalValues["beta_testers"].Value != "true" {
+		t.Fatalf("incorrect parameter: %#v", p)
+	}
+	if d.Id() != "new_checkout" || d.Get("group") != "checkout" || d.Get("created_group") != "checkout" || d.Get("value_type") != "BOOLEAN" {
+		t.Fatalf("incorrect state: %s %s %s %s", d.Id(), d.Get("group"), d.Get("created_group"), d.Get("value_type"))
+	}
+
+	// Updating the last parameter of a group keeps the group
+	fake.Lock()
+	current.ParameterGroups["checkout"].Description = "Checkout flow"
+	fake.Unlock()
+	diff, err := r.Diff(d.State(), testResourceConfig(t, map[string]interface{}{
+		"key":           "new_checkout",
+		"default_value": "false",
+		"value_type":    "BOOLEAN",
+		"description":   "Checkout rewrite",
+		"group":         "checkout",
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if g := fake.current().ParameterGroups["checkout"]; g == nil || g.Description != "Checkout flow" || g.Parameters["new_checkout"].Description != "Checkout rewrite" {
+		t.Fatalf("group not kept: %#v", g)
+	}
+
+	// Moving the parameter out of the group it created removes the group
+	diff, err = r.Diff(state, testResourceConfig(t, map[string]interface{}{
+		"key":           "new_checkout",
+		"default_value": "true",
+		"value_type":    "BOOLEAN",
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = r.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	current = fake.current()
+	if current.ParameterGroups["checkout"] != nil || *current.Parameters["new_checkout"].DefaultValue.Value != "true" {
+		t.Fatalf("parameter not moved: %#v", current)
+	}
+	if state.Attributes["group"] != "" || state.Attributes["conditional_value.#"] != "0" {
+		t.Fatalf("incorrect state: %v", state.Attributes)
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"key":           "new_checkout",
+		"default_value": "false",
+	})
+	if err := r.Create(d, meta); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected existing parameter error, got: %v", err)
+	}
+
+	d = r.Data(state)
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	current = fake.current()
+	if current.Parameters["new_checkout"] != nil || current.Parameters["other_team_flag_0"] == nil {
+		t.Fatalf("incorrect parameters after delete: %v", current.Parameters)
+	}
+}
+
+func TestResourceFirebaseRemoteConfigParameter_existingGroup(t *testing.T) {
+	fake := newTestRemoteConfigServer()
+	meta, closeServer := testAPIClient(t, fake, remoteConfigEndpoint)
+	defer closeServer()
+
+	fake.Lock()
+	fake.publish(&remoteConfigTemplate{
+		ParameterGroups: map[string]*remoteConfigParameterGroup{
+			"onboarding": {Description: "Managed in the console"},
+		},
+	})
+	fake.Unlock()
+
+	r := resourceFirebaseRemoteConfigParameter()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"key":           "welcome_flow",
+		"default_value": "v2",
+		"group":         "onboarding",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("created_group") != "" || fake.current().ParameterGroups["onboarding"].Parameters["welcome_flow"] == nil {
+		t.Fatalf("incorrect parameter: %q %#v", d.Get("created_group"), fake.current().ParameterGroups)
+	}
+
+	// Groups the parameter did not create are kept once empty
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	g := fake.current().ParameterGroups["onboarding"]
+	if g == nil || g.Description != "Managed in the console" || len(g.Parameters) != 0 {
+		t.Fatalf("incorrect group after delete: %#v", g)
+	}
+}
@@ -0,0 +1,566 @@
+package firebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const remoteConfigEndpoint = "https://firebaseremoteconfig.googleapis.com/v1"
+
+type remoteConfigTemplate struct {
+	Conditions      []*remoteConfigCondition               `json:"conditions,omitempty"`
+	Parameters      map[string]*remoteConfigParameter      `json:"parameters,omitempty"`
+	ParameterGroups map[string]*remoteConfigParameterGroup `json:"parameterGroups,omitempty"`
+	Version         *remoteConfigVersion                   `json:"version,omitempty"`
+}
+
+type remoteConfigCondition struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	TagColor   string `json:"tagColor,omitempty"`
+}
+
+type remoteConfigParameter struct {
+	DefaultValue      *remoteConfigValue            `json:"defaultValue,omitempty"`
+	ConditionalValues map[string]*remoteConfigValue `json:"conditionalValues,omitempty"`
+	Description       string                        `json:"description,omitempty"`
+	ValueType         string                        `json:"valueType,omitempty"`
+}
+
+type remoteConfigValue struct {
+	Value           *string `json:"value,omitempty"`
+	UseInAppDefault bool    `json:"useInAppDefault,omitempty"`
+}
+
+type remoteConfigParameterGroup struct {
+	Description string                            `json:"description,omitempty"`
+	Parameters  map[string]*remoteConfigParameter `json:"parameters,omitempty"`
+}
+
+type remoteConfigVersion struct {
+	VersionNumber string `json:"versionNumber,omitempty"`
+	UpdateTime    string `json:"updateTime,omitempty"`
+	Description   string `json:"description,omitempty"`
+	UpdateUser    *struct {
+		Email string `json:"email"`
+	} `json:"updateUser,omitempty"`
+}
+
+// remoteConfigTemplateKeys are the keys published as the template.
+var remoteConfigTemplateKeys = []string{"condition", "parameter", "parameter_group", "description"}
+
+func resourceFirebaseRemoteConfigTemplate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseRemoteConfigTemplateCreate,
+		Read:   resourceFirebaseRemoteConfigTemplateRead,
+		Update: resourceFirebaseRemoteConfigTemplateUpdate,
+		Delete: resourceFirebaseRemoteConfigTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseRemoteConfigTemplateImportState,
+		},
+
+		CustomizeDiff: resourceFirebaseRemoteConfigTemplateCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			// The order of conditions is their priority
+			"condition": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"expression": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"tag_color": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"BLUE", "BROWN", "CYAN", "DEEP_ORANGE", "GREEN", "INDIGO",
+								"LIME", "ORANGE", "PINK", "PURPLE", "TEAL",
+							}, false),
+						},
+					},
+				},
+			},
+			"parameter": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"default_value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"use_in_app_default": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"conditional_value": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     remoteConfigConditionalValueResource(),
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"value_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "STRING",
+							ValidateFunc: validation.StringInSlice([]string{
+								"STRING", "BOOLEAN", "NUMBER", "JSON",
+							}, false),
+						},
+						"group": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"parameter_group": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			// Description of the published version, it is not read back
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Publishes a rollback to this version when set or changed, in
+			// place of the configured template. Differences between the
+			// version and the configuration show on the next plan.
+			"rollback_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"version_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"update_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"update_user": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"etag": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func remoteConfigConditionalValueResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"condition": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"use_in_app_default": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func remoteConfigURL(project string) string {
+	return fmt.Sprintf("%s/projects/%s/remoteConfig", remoteConfigEndpoint, project)
+}
+
+func resourceFirebaseRemoteConfigTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Creating Remote Config template of project: %s", client.ProjectID)
+
+	// Every project has a template, publish over the current one
+	var current remoteConfigTemplate
+	header, err := sendRequestWithHeader(context.Background(), client.HTTP, "GET", remoteConfigURL(client.ProjectID), nil, nil, &current)
+	if err != nil {
+		return fmt.Errorf("Error reading Remote Config template (%s): %s", client.ProjectID, err)
+	}
+
+	d.SetId(client.ProjectID)
+
+	err = publishRemoteConfigTemplate(d, client, header.Get("ETag"), &current, true)
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+
+	return resourceFirebaseRemoteConfigTemplateRead(d, meta)
+}
+
+func resourceFirebaseRemoteConfigTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading Remote Config template: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var template remoteConfigTemplate
+	header, err := sendRequestWithHeader(context.Background(), client.HTTP, "GET", remoteConfigURL(client.ProjectID), nil, nil, &template)
+	if err != nil {
+		return fmt.Errorf("Error reading Remote Config template (%s): %s", d.Id(), err)
+	}
+
+	if err := d.Set("condition", flattenRemoteConfigConditions(template.Conditions)); err != nil {
+		return fmt.Errorf("Error setting condition: %s", err)
+	}
+	parameters, groups := flattenRemoteConfigParameters(&template)
+	if err := d.Set("parameter", parameters); err != nil {
+		return fmt.Errorf("Error setting parameter: %s", err)
+	}
+	if err := d.Set("parameter_group", groups); err != nil {
+		return fmt.Errorf("Error setting parameter_group: %s", err)
+	}
+	d.Set("etag", header.Get("ETag"))
+	if v := template.Version; v != nil {
+		n, _ := strconv.Atoi(v.VersionNumber)
+		d.Set("version_number", n)
+		d.Set("update_time", v.UpdateTime)
+		if v.UpdateUser != nil {
+			d.Set("update_user", v.UpdateUser.Email)
+		}
+	}
+
+	return nil
+}
+
+func resourceFirebaseRemoteConfigTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, k := range remoteConfigTemplateKeys {
+		changed = changed || d.HasChange(k)
+	}
+	if changed || d.HasChange("rollback_version") {
+		err := publishRemoteConfigTemplate(d, client, d.Get("etag").(string), nil, changed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceFirebaseRemoteConfigTemplateRead(d, meta)
+}
+
+func resourceFirebaseRemoteConfigTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	// A project always has a template, the published versions are kept
+	log.Printf("[INFO] Forgetting Remote Config template: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// resourceFirebaseRemoteConfigTemplateImportState accepts a project ID.
+func resourceFirebaseRemoteConfigTemplateImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("project", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceFirebaseRemoteConfigTemplateCustomizeDiff validates the template
+// with the Remote Config API, without publishing it, whenever it changes.
+// A rollback cannot be planned along with changes to the template, which it
+// would discard.
+func resourceFirebaseRemoteConfigTemplateCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	rollback := d.HasChange("rollback_version") && d.Get("rollback_version").(int) > 0
+	changed := d.Id() == ""
+	for _, k := range remoteConfigTemplateKeys {
+		if !d.NewValueKnown(k) {
+			log.Printf("[DEBUG] Skipping validation, %s is not known yet", k)
+			return nil
+		}
+		if rollback && d.HasChange(k) {
+			return fmt.Errorf("rollback_version cannot change along with %s, roll back first and then change the template", k)
+		}
+		changed = changed || d.HasChange(k)
+	}
+	if !changed {
+		return nil
+	}
+
+	template, err := expandRemoteConfigTemplate(d.Get)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Validating Remote Config template")
+
+	client, err := meta.(Client).forProject(d.Get("project").(string))
+	if err != nil {
+		return err
+	}
+	etag := d.Get("etag").(string)
+	if etag == "" {
+		etag = "*"
+	}
+	_, err = sendRequestWithHeader(context.Background(), client.HTTP, "PUT", remoteConfigURL(client.ProjectID)+"?validateOnly=true",
+		http.Header{"If-Match": {etag}}, template, nil)
+	if err != nil {
+		return fmt.Errorf("Error validating Remote Config template: %s", err)
+	}
+	return nil
+}
+
+// publishRemoteConfigTemplate publishes the configured template if the
+// current ETag still matches. Nothing is published when current already
+// matches the configuration. When rollback_version is set and changed, a
+// rollback to that version is published instead.
+func publishRemoteConfigTemplate(d *schema.ResourceData, client Client, etag string, current *remoteConfigTemplate, changed bool) error {
+	if v := d.Get("rollback_version").(int); v > 0 && d.HasChange("rollback_version") {
+		log.Printf("[INFO] Rolling back Remote Config template %s to version %d", d.Id(), v)
+
+		err := sendRequest(context.Background(), client.HTTP, "POST", remoteConfigURL(client.ProjectID)+":rollback",
+			map[string]string{"versionNumber": strconv.Itoa(v)}, nil)
+		if err != nil {
+			return fmt.Errorf("Error rolling back Remote Config template (%s) to version %d: %s", d.Id(), v, err)
+		}
+		return nil
+	}
+
+	template, err := expandRemoteConfigTemplate(d.Get)
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		same, err := sameRemoteConfigTemplate(template, current)
+		if err != nil {
+			return err
+		}
+		changed = !same
+	}
+	if !changed {
+		return nil
+	}
+
+	log.Printf("[INFO] Publishing Remote Config template: %s", d.Id())
+
+	_, err = sendRequestWithHeader(context.Background(), client.HTTP, "PUT", remoteConfigURL(client.ProjectID),
+		http.Header{"If-Match": {etag}}, template, nil)
+	if err != nil {
+		if isPreconditionFailed(err) {
+			return fmt.Errorf("Error publishing Remote Config template (%s): the template was published outside of Terraform, refresh and plan again: %s", d.Id(), err)
+		}
+		return fmt.Errorf("Error publishing Remote Config template (%s): %s", d.Id(), err)
+	}
+	return nil
+}
+
+// sameRemoteConfigTemplate reports whether a and b have the same conditions,
+// parameters and parameter groups.
+func sameRemoteConfigTemplate(a, b *remoteConfigTemplate) (bool, error) {
+	content := func(t *remoteConfigTemplate) ([]byte, error) {
+		return json.Marshal(&remoteConfigTemplate{
+			Conditions:      t.Conditions,
+			Parameters:      t.Parameters,
+			ParameterGroups: t.ParameterGroups,
+		})
+	}
+	ca, err := content(a)
+	if err != nil {
+		return false, err
+	}
+	cb, err := content(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ca, cb), nil
+}
+
+func expandRemoteConfigTemplate(get func(string) interface{}) (*remoteConfigTemplate, error) {
+	template := &remoteConfigTemplate{
+		Parameters:      make(map[string]*remoteConfigParameter),
+		ParameterGroups: make(map[string]*remoteConfigParameterGroup),
+	}
+	if v := get("description").(string); v != "" {
+		template.Version = &remoteConfigVersion{Description: v}
+	}
+
+	for _, v := range get("condition").([]interface{}) {
+		c := v.(map[string]interface{})
+		template.Conditions = append(template.Conditions, &remoteConfigCondition{
+			Name:       c["name"].(string),
+			Expression: c["expression"].(string),
+			TagColor:   c["tag_color"].(string),
+		})
+	}
+
+	for _, v := range get("parameter_group").(*schema.Set).List() {
+		g := v.(map[string]interface{})
+		name := g["name"].(string)
+		if template.ParameterGroups[name] != nil {
+			return nil, fmt.Errorf("Parameter group %q is declared more than once", name)
+		}
+		template.ParameterGroups[name] = &remoteConfigParameterGroup{
+			Description: g["description"].(string),
+			Parameters:  make(map[string]*remoteConfigParameter),
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range get("parameter").(*schema.Set).List() {
+		p := v.(map[string]interface{})
+		key := p["key"].(string)
+		if seen[key] {
+			return nil, fmt.Errorf("Parameter %q is declared more than once", key)
+		}
+		seen[key] = true
+
+		parameter := expandRemoteConfigParameter(p)
+		group := p["group"].(string)
+		if group == "" {
+			template.Parameters[key] = parameter
+			continue
+		}
+		if template.ParameterGroups[group] == nil {
+			template.ParameterGroups[group] = &remoteConfigParameterGroup{
+				Parameters: make(map[string]*remoteConfigParameter),
+			}
+		}
+		template.ParameterGroups[group].Parameters[key] = parameter
+	}
+
+	return template, nil
+}
+
+// expandRemoteConfigParameter expands the fields of a parameter, other than
+// its key and group.
+func expandRemoteConfigParameter(p map[string]interface{}) *remoteConfigParameter {
+	parameter := &remoteConfigParameter{
+		DefaultValue: expandRemoteConfigValue(p["default_value"].(string), p["use_in_app_default"].(bool)),
+		Description:  p["description"].(string),
+		ValueType:    p["value_type"].(string),
+	}
+	for _, v := range p["conditional_value"].(*schema.Set).List() {
+		c := v.(map[string]interface{})
+		if parameter.ConditionalValues == nil {
+			parameter.ConditionalValues = make(map[string]*remoteConfigValue)
+		}
+		parameter.ConditionalValues[c["condition"].(string)] = expandRemoteConfigValue(c["value"].(string), c["use_in_app_default"].(bool))
+	}
+	return parameter
+}
+
+func expandRemoteConfigValue(value string, useInAppDefault bool) *remoteConfigValue {
+	if useInAppDefault {
+		return &remoteConfigValue{UseInAppDefault: true}
+	}
+	return &remoteConfigValue{Value: &value}
+}
+
+func flattenRemoteConfigConditions(conditions []*remoteConfigCondition) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		result = append(result, map[string]interface{}{
+			"name":       c.Name,
+			"expression": c.Expression,
+			"tag_color":  c.TagColor,
+		})
+	}
+	return result
+}
+
+// flattenRemoteConfigParameters returns the parameters, including those of
+// groups, and the groups of a template.
+func flattenRemoteConfigParameters(template *remoteConfigTemplate) ([]map[string]interface{}, []map[string]interface{}) {
+	var parameters, groups []map[string]interface{}
+	for key, p := range template.Parameters {
+		parameters = append(parameters, flattenRemoteConfigParameter(key, "", p))
+	}
+	names := make([]string, 0, len(template.ParameterGroups))
+	for name := range template.ParameterGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g := template.ParameterGroups[name]
+		groups = append(groups, map[string]interface{}{
+			"name":        name,
+			"description": g.Description,
+		})
+		for key, p := range g.Parameters {
+			parameters = append(parameters, flattenRemoteConfigParameter(key, name, p))
+		}
+	}
+	return parameters, groups
+}
+
+func flattenRemoteConfigParameter(key, group string, p *remoteConfigParameter) map[string]interface{} {
+	parameter := map[string]interface{}{
+		"key":         key,
+		"description": p.Description,
+		"value_type":  p.ValueType,
+		"group":       group,
+	}
+	if parameter["value_type"] == "" || parameter["value_type"] == "PARAMETER_VALUE_TYPE_UNSPECIFIED" {
+		parameter["value_type"] = "STRING"
+	}
+	if v := p.DefaultValue; v != nil {
+		parameter["use_in_app_default"] = v.UseInAppDefault
+		if v.Value != nil {
+			parameter["default_value"] = *v.Value
+		}
+	}
+	// Sets nested in sets cannot be set from slices
+	conditionalValues := schema.NewSet(schema.HashResource(remoteConfigConditionalValueResource()), nil)
+	for condition, v := range p.ConditionalValues {
+		c := map[string]interface{}{
+			"condition":          condition,
+			"use_in_app_default": v.UseInAppDefault,
+		}
+		if v.Value != nil {
+			c["value"] = *v.Value
+		}
+		conditionalValues.Add(c)
+	}
+	parameter["conditional_value"] = conditionalValues
+	return parameter
+}
@@ -0,0 +1,310 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testRemoteConfigServer fakes the Remote Config template of a project and
+// keeps every published version.
+type testRemoteConfigServer struct {
+	sync.Mutex
+	versions  []*remoteConfigTemplate
+	published int
+	validated int
+	// conflicts is the number of publishes that race with a publish of
+	// another parameter, as another workspace would
+	conflicts int
+}
+
+func newTestRemoteConfigServer() *testRemoteConfigServer {
+	s := &testRemoteConfigServer{}
+	s.publish(&remoteConfigTemplate{})
+	s.published = 0
+	return s
+}
+
+func (s *testRemoteConfigServer) current() *remoteConfigTemplate {
+	return s.versions[len(s.versions)-1]
+}
+
+func (s *testRemoteConfigServer) publish(t *remoteConfigTemplate) {
+	t.Version = &remoteConfigVersion{
+		VersionNumber: strconv.Itoa(len(s.versions) + 1),
+		UpdateTime:    "2026-10-19T00:00:00Z",
+	}
+	s.versions = append(s.versions, t)
+	s.published++
+}
+
+func (s *testRemoteConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	etag := func() string {
+		return "etag-" + s.current().Version.VersionNumber
+	}
+	reply := func(t *remoteConfigTemplate) {
+		w.Header().Set("ETag", etag())
+		json.NewEncoder(w).Encode(t)
+	}
+	fail := func(code int, message string) {
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+	}
+
+	switch {
+	case r.Method == "GET":
+		reply(s.current())
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, ":rollback"):
+		var body struct {
+			VersionNumber string `json:"versionNumber"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		n, _ := strconv.Atoi(body.VersionNumber)
+		if n < 1 || n > len(s.versions) {
+			fail(http.StatusNotFound, "version not found")
+			return
+		}
+		b, _ := json.Marshal(s.versions[n-1])
+		var t remoteConfigTemplate
+		json.Unmarshal(b, &t)
+		s.publish(&t)
+		reply(&t)
+	case r.Method == "PUT":
+		if s.conflicts > 0 && r.URL.Query().Get("validateOnly") == "" {
+			s.conflicts--
+			b, _ := json.Marshal(s.current())
+			var t remoteConfigTemplate
+			json.Unmarshal(b, &t)
+			if t.Parameters == nil {
+				t.Parameters = make(map[string]*remoteConfigParameter)
+			}
+			value := "true"
+			t.Parameters[fmt.Sprintf("other_team_flag_%d", s.conflicts)] = &remoteConfigParameter{
+				DefaultValue: &remoteConfigValue{Value: &value},
+			}
+			s.publish(&t)
+		}
+		if m := r.Header.Get("If-Match"); m != "*" && m != etag() {
+			fail(http.StatusPreconditionFailed, "ETag mismatch")
+			return
+		}
+		var t remoteConfigTemplate
+		json.NewDecoder(r.Body).Decode(&t)
+		for _, p := range t.Parameters {
+			for condition := range p.ConditionalValues {
+				if !hasRemoteConfigCondition(&t, condition) {
+					fail(http.StatusBadRequest, fmt.Sprintf("[VALIDATION_ERROR]: Unknown condition %s", condition))
+					return
+				}
+			}
+		}
+		if r.URL.Query().Get("validateOnly") == "true" {
+			s.validated++
+			reply(&t)
+			return
+		}
+		s.publish(&t)
+		reply(&t)
+	default:
+		fail(http.StatusBadRequest, "unexpected request")
+	}
+}
+
+func hasRemoteConfigCondition(t *remoteConfigTemplate, name string) bool {
+	for _, c := range t.Conditions {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var testRemoteConfigTemplate = map[string]interface{}{
+	"condition": []interface{}{
+		map[string]interface{}{"name": "ios", "expression": "device.os == 'ios'", "tag_color": "BLUE"},
+	},
+	"parameter": []interface{}{
+		map[string]interface{}{
+			"key":           "new_checkout",
+			"default_value": "false",
+			"value_type":    "BOOLEAN",
+			"group":         "checkout",
+			"conditional_value": []interface{}{
+				map[string]interface{}{"condition": "ios", "value": "true"},
+			},
+		},
+		map[string]interface{}{
+			"key":                "welcome_message",
+			"use_in_app_default": true,
+		},
+	},
+	"parameter_group": []interface{}{
+		map[string]interface{}{"name": "checkout", "description": "Checkout flow"},
+	},
+}
+
+func TestResourceFirebaseRemoteConfigTemplate(t *testing.T) {
+	fake := newTestRemoteConfigServer()
+	meta, closeServer := testAPIClient(t, fake, remoteConfigEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseRemoteConfigTemplate()
+	diff, err := r.Diff(nil, testResourceConfig(t, testRemoteConfigTemplate), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.validated == 0 || fake.published != 0 {
+		t.Fatalf("template not only validated at plan time: %d validated, %d published", fake.validated, fake.published)
+	}
+	state, err := r.Apply(nil, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.published != 1 || state.ID != testProjectID {
+		t.Fatalf("template not published: %d %s", fake.published, state.ID)
+	}
+	if state.Attributes["version_number"] != "2" || state.Attributes["etag"] != "etag-2" {
+		t.Fatalf("incorrect version: %s %s", state.Attributes["version_number"], state.Attributes["etag"])
+	}
+	group := fake.current().ParameterGroups["checkout"]
+	if group == nil || group.Description != "Checkout flow" || *group.Parameters["new_checkout"].ConditionalValues["ios"].Value != "true" {
+		t.Fatalf("incorrect parameter group: %#v", group)
+	}
+	if !fake.current().Parameters["welcome_message"].DefaultValue.UseInAppDefault {
+		t.Fatalf("incorrect parameter: %#v", fake.current().Parameters["welcome_message"])
+	}
+
+	// Published templates read back without changes
+	diff, err = r.Diff(state, testResourceConfig(t, testRemoteConfigTemplate), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("unexpected diff: %#v", diff)
+	}
+
+	// Out of band edits are drift
+	fake.Lock()
+	edited := *fake.current()
+	edited.Conditions = nil
+	edited.Parameters = nil
+	fake.publish(&edited)
+	fake.Unlock()
+
+	d := r.Data(state)
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err = r.Diff(d.State(), testResourceConfig(t, testRemoteConfigTemplate), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff.Empty() {
+		t.Fatalf("out of band edit not detected")
+	}
+
+	// Publishing over a version published since the plan fails
+	fake.Lock()
+	fake.publish(&remoteConfigTemplate{})
+	fake.Unlock()
+	_, err = r.Apply(d.State(), diff, meta)
+	if err == nil || !strings.Contains(err.Error(), "refresh and plan again") {
+		t.Fatalf("expected ETag mismatch, got: %v", err)
+	}
+}
+
+func TestResourceFirebaseRemoteConfigTemplate_validation(t *testing.T) {
+	fake := newTestRemoteConfigServer()
+	meta, closeServer := testAPIClient(t, fake, remoteConfigEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseRemoteConfigTemplate()
+	_, err := r.Diff(nil, testResourceConfig(t, map[string]interface{}{
+		"parameter": []interface{}{
+			map[string]interface{}{
+				"key":           "new_checkout",
+				"default_value": "false",
+				"conditional_value": []interface{}{
+					map[string]interface{}{"condition": "android", "value": "true"},
+				},
+			},
+		},
+	}), meta)
+	if err == nil || !strings.Contains(err.Error(), "Unknown condition android") {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+	if fake.published != 0 {
+		t.Fatalf("invalid template was published")
+	}
+}
+
+func TestResourceFirebaseRemoteConfigTemplate_rollback(t *testing.T) {
+	fake := newTestRemoteConfigServer()
+	meta, closeServer := testAPIClient(t, fake, remoteConfigEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseRemoteConfigTemplate()
+	d := schema.TestResourceDataRaw(t, r.Schema, testRemoteConfigTemplate)
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Version 3 drops the parameters of version 2
+	config := map[string]interface{}{
+		"condition":       testRemoteConfigTemplate["condition"],
+		"parameter_group": testRemoteConfigTemplate["parameter_group"],
+	}
+	diff, err := r.Diff(d.State(), testResourceConfig(t, config), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Rolling back to version 2 publishes it, not the configuration
+	config["rollback_version"] = 2
+	diff, err = r.Diff(state, testResourceConfig(t, config), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err = r.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.published != 3 || state.Attributes["version_number"] != "4" {
+		t.Fatalf("incorrect rollback: %d published, version %s", fake.published, state.Attributes["version_number"])
+	}
+	if len(fake.current().Parameters) != 1 || state.Attributes["parameter.#"] != "2" {
+		t.Fatalf("rollback not kept: %v", fake.current().Parameters)
+	}
+
+	// The parameters of the rolled back version are a change to the configuration
+	diff, err = r.Diff(state, testResourceConfig(t, config), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff.Empty() || diff.Attributes["parameter.#"] == nil || diff.Attributes["parameter.#"].New != "0" {
+		t.Fatalf("rollback not shown as a change: %#v", diff)
+	}
+	if fake.published != 3 {
+		t.Fatalf("template published at plan time")
+	}
+
+	// Another rollback would discard the changes planned along with it
+	config["rollback_version"] = 1
+	_, err = r.Diff(state, testResourceConfig(t, config), meta)
+	if err == nil || !strings.Contains(err.Error(), "rollback_version cannot change along with parameter") {
+		t.Fatalf("expected rollback error, got: %v", err)
+	}
+}
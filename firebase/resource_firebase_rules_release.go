@@ -0,0 +1,305 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type rulesRelease struct {
+	Name        string `json:"name"`
+	RulesetName string `json:"rulesetName"`
+	CreateTime  string `json:"createTime,omitempty"`
+	UpdateTime  string `json:"updateTime,omitempty"`
+}
+
+func resourceFirebaseRulesRelease() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseRulesReleaseCreate,
+		Read:   resourceFirebaseRulesReleaseRead,
+		Update: resourceFirebaseRulesReleaseUpdate,
+		Delete: resourceFirebaseRulesReleaseDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseRulesReleaseImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRulesReleaseName,
+			},
+			"ruleset_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Deletes all but the most recent rulesets of the release's
+			// service that are not in use by a release. Rulesets created after
+			// the released one are kept.
+			"retain_rulesets": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"create_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"update_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func rulesReleaseURL(project, release string) string {
+	return fmt.Sprintf("%s/releases/%s", rulesProjectURL(project), release)
+}
+
+func resourceFirebaseRulesReleaseCreate(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+	log.Printf("[INFO] Creating rules release: %s", name)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Projects usually have a release for their database and default
+	// bucket already, which is taken over
+	err = updateRulesRelease(client, name, d.Get("ruleset_name").(string))
+	if isNotFound(err) {
+		release := &rulesRelease{
+			Name:        fmt.Sprintf("projects/%s/releases/%s", client.ProjectID, name),
+			RulesetName: d.Get("ruleset_name").(string),
+		}
+		err = sendRequest(context.Background(), client.HTTP, "POST", rulesProjectURL(client.ProjectID)+"/releases", release, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating rules release (%s): %s", name, err)
+	}
+
+	d.SetId(name)
+
+	if err := collectRulesRulesets(client, d.Id(), d.Get("ruleset_name").(string), d.Get("retain_rulesets").(int)); err != nil {
+		return err
+	}
+
+	return resourceFirebaseRulesReleaseRead(d, meta)
+}
+
+func resourceFirebaseRulesReleaseRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading rules release: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var release rulesRelease
+	err = sendRequest(context.Background(), client.HTTP, "GET", rulesReleaseURL(client.ProjectID, d.Id()), nil, &release)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Rules release (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading rules release (%s): %s", d.Id(), err)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("ruleset_name", release.RulesetName)
+	d.Set("create_time", release.CreateTime)
+	d.Set("update_time", release.UpdateTime)
+
+	return nil
+}
+
+func resourceFirebaseRulesReleaseUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("ruleset_name") {
+		log.Printf("[INFO] Updating rules release: %s", d.Id())
+
+		if err := updateRulesRelease(client, d.Id(), d.Get("ruleset_name").(string)); err != nil {
+			return fmt.Errorf("Error updating rules release (%s): %s", d.Id(), err)
+		}
+	}
+
+	if err := collectRulesRulesets(client, d.Id(), d.Get("ruleset_name").(string), d.Get("retain_rulesets").(int)); err != nil {
+		return err
+	}
+
+	return resourceFirebaseRulesReleaseRead(d, meta)
+}
+
+func resourceFirebaseRulesReleaseDelete(d *schema.ResourceData, meta interface{}) error {
+	// Without a release every request would be denied, the release keeps
+	// serving its last ruleset instead
+	log.Printf("[INFO] Forgetting rules release: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// resourceFirebaseRulesReleaseImportState accepts a release name, such as
+// cloud.firestore, or projects/<project>/releases/<name>.
+func resourceFirebaseRulesReleaseImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if parts := strings.SplitN(d.Id(), "/", 4); len(parts) == 4 && parts[0] == "projects" && parts[2] == "releases" {
+		d.Set("project", parts[1])
+		d.SetId(parts[3])
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// updateRulesRelease points an existing release to another ruleset, which
+// takes effect atomically.
+func updateRulesRelease(client Client, name, ruleset string) error {
+	body := map[string]interface{}{
+		"release": &rulesRelease{
+			Name:        fmt.Sprintf("projects/%s/releases/%s", client.ProjectID, name),
+			RulesetName: ruleset,
+		},
+	}
+	return sendRequest(context.Background(), client.HTTP, "PATCH", rulesReleaseURL(client.ProjectID, name), body, nil)
+}
+
+// rulesRulesetsInUse returns the names of the rulesets used by releases of
+// the client's project.
+func rulesRulesetsInUse(client Client) (map[string]bool, error) {
+	inUse := make(map[string]bool)
+	token := ""
+	for {
+		var page struct {
+			Releases      []rulesRelease `json:"releases"`
+			NextPageToken string         `json:"nextPageToken"`
+		}
+		u := fmt.Sprintf("%s/releases?pageToken=%s", rulesProjectURL(client.ProjectID), url.QueryEscape(token))
+		if err := sendRequest(context.Background(), client.HTTP, "GET", u, nil, &page); err != nil {
+			return nil, fmt.Errorf("Error listing rules releases: %s", err)
+		}
+		for _, r := range page.Releases {
+			inUse[r.RulesetName] = true
+		}
+		if token = page.NextPageToken; token == "" {
+			return inUse, nil
+		}
+	}
+}
+
+// rulesServicePattern matches the service declaration of rules files.
+var rulesServicePattern = regexp.MustCompile(`(?m)^\s*service\s+([a-z.]+)`)
+
+// rulesRulesetService returns the service the rules of a ruleset are
+// declared for, cloud.firestore or firebase.storage, or "" if unknown.
+func rulesRulesetService(ruleset *rulesRuleset) string {
+	if ruleset.Source == nil {
+		return ""
+	}
+	for _, f := range ruleset.Source.Files {
+		if m := rulesServicePattern.FindStringSubmatch(f.Content); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// collectRulesRulesets deletes the rulesets of the release's service beyond
+// the retain most recent ones, except for those in use by a release. Only
+// rulesets created before the released ruleset are considered, so those
+// about to be released by other releases are kept. Nothing is deleted when
+// retain is 0.
+func collectRulesRulesets(client Client, release, released string, retain int) error {
+	if retain == 0 {
+		return nil
+	}
+
+	var current rulesRuleset
+	if err := sendRequest(context.Background(), client.HTTP, "GET", rulesEndpoint+"/"+released, nil, &current); err != nil {
+		return fmt.Errorf("Error reading rules ruleset (%s): %s", released, err)
+	}
+	service := strings.SplitN(release, "/", 2)[0]
+	releasedTime, err := time.Parse(time.RFC3339Nano, current.CreateTime)
+	if err != nil {
+		return fmt.Errorf("Error parsing create time of rules ruleset (%s): %s", released, err)
+	}
+
+	// Timestamps carry 0 to 9 fractional digits, so only compare them parsed
+	var rulesets []rulesRuleset
+	created := make(map[string]time.Time)
+	token := ""
+	for {
+		var page struct {
+			Rulesets      []rulesRuleset `json:"rulesets"`
+			NextPageToken string         `json:"nextPageToken"`
+		}
+		u := fmt.Sprintf("%s/rulesets?pageToken=%s", rulesProjectURL(client.ProjectID), url.QueryEscape(token))
+		if err := sendRequest(context.Background(), client.HTTP, "GET", u, nil, &page); err != nil {
+			return fmt.Errorf("Error listing rules rulesets: %s", err)
+		}
+		for _, r := range page.Rulesets {
+			t, err := time.Parse(time.RFC3339Nano, r.CreateTime)
+			if err != nil {
+				return fmt.Errorf("Error parsing create time of rules ruleset (%s): %s", r.Name, err)
+			}
+			if !t.After(releasedTime) {
+				rulesets = append(rulesets, r)
+				created[r.Name] = t
+			}
+		}
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+	if len(rulesets) <= retain {
+		return nil
+	}
+
+	inUse, err := rulesRulesetsInUse(client)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(rulesets, func(i, j int) bool {
+		return created[rulesets[i].Name].After(created[rulesets[j].Name])
+	})
+	kept := 0
+	for _, r := range rulesets {
+		// Listed rulesets have no source, which tells their service
+		if err := sendRequest(context.Background(), client.HTTP, "GET", rulesEndpoint+"/"+r.Name, nil, &r); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("Error reading rules ruleset (%s): %s", r.Name, err)
+		}
+		if rulesRulesetService(&r) != service {
+			continue
+		}
+		if kept < retain {
+			kept++
+			continue
+		}
+		if inUse[r.Name] {
+			continue
+		}
+		log.Printf("[INFO] Deleting old rules ruleset: %s", r.Name)
+		err := sendRequest(context.Background(), client.HTTP, "DELETE", rulesEndpoint+"/"+r.Name, nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("Error deleting old rules ruleset (%s): %s", r.Name, err)
+		}
+	}
+	return nil
+}
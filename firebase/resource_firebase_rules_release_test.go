@@ -0,0 +1,330 @@
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testRulesServer fakes the Rules API. Lines of rules containing "!!" fail
+// to compile, and test cases are evaluated as if the rules only allowed
+// signed in users to read.
+type testRulesServer struct {
+	sync.Mutex
+	rulesets map[string]*rulesRuleset
+	releases map[string]*rulesRelease
+}
+
+func (s *testRulesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1/")
+	project := fmt.Sprintf("projects/%s", testProjectID)
+	fail := func(code int, message string) {
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"error":{"code":%d,"message":%q}}`, code, message)
+	}
+
+	switch {
+	case name == project+":test":
+		var ruleset struct {
+			rulesRuleset
+			TestSuite struct {
+				TestCases []rulesTestCase `json:"testCases"`
+			} `json:"testSuite"`
+		}
+		json.NewDecoder(r.Body).Decode(&ruleset)
+		var results []map[string]interface{}
+		for _, c := range ruleset.TestSuite.TestCases {
+			allowed := c.Request["auth"] != nil && (c.Request["method"] == "get" || c.Request["method"] == "list")
+			switch {
+			case allowed == (c.Expectation == "ALLOW"):
+				results = append(results, map[string]interface{}{"state": "SUCCESS"})
+			case allowed:
+				results = append(results, map[string]interface{}{"state": "FAILURE"})
+			default:
+				results = append(results, map[string]interface{}{
+					"state":         "FAILURE",
+					"debugMessages": []string{"Null value error."},
+					"errorPosition": map[string]interface{}{"fileName": "firestore.rules", "line": 3, "column": 22},
+				})
+			}
+		}
+		var issues []map[string]interface{}
+		for _, f := range ruleset.Source.Files {
+			for i, line := range strings.Split(f.Content, "\n") {
+				if c := strings.Index(line, "!!"); c >= 0 {
+					issues = append(issues, map[string]interface{}{
+						"sourcePosition": map[string]interface{}{"fileName": f.Name, "line": i + 1, "column": c + 1},
+						"description":    "Unexpected '!!'.",
+						"severity":       "ERROR",
+					})
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues, "testResults": results})
+	case name == project+"/rulesets" && r.Method == "POST":
+		var ruleset rulesRuleset
+		json.NewDecoder(r.Body).Decode(&ruleset)
+		ruleset.Name = fmt.Sprintf("%s/rulesets/r%d", project, len(s.rulesets)+1)
+		ruleset.CreateTime = fmt.Sprintf("2026-10-19T00:00:%02dZ", len(s.rulesets)+1)
+		s.rulesets[ruleset.Name] = &ruleset
+		json.NewEncoder(w).Encode(&ruleset)
+	case name == project+"/rulesets":
+		var rulesets []*rulesRuleset
+		for _, ruleset := range s.rulesets {
+			rulesets = append(rulesets, &rulesRuleset{Name: ruleset.Name, CreateTime: ruleset.CreateTime})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"rulesets": rulesets})
+	case name == project+"/releases" && r.Method == "POST":
+		var release rulesRelease
+		json.NewDecoder(r.Body).Decode(&release)
+		s.releases[release.Name] = &release
+		json.NewEncoder(w).Encode(&release)
+	case name == project+"/releases":
+		var releases []*rulesRelease
+		for _, release := range s.releases {
+			releases = append(releases, release)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"releases": releases})
+	case strings.HasPrefix(name, project+"/rulesets/"):
+		ruleset := s.rulesets[name]
+		if ruleset == nil {
+			fail(http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		if r.Method == "DELETE" {
+			for _, release := range s.releases {
+				if release.RulesetName == name {
+					fail(http.StatusBadRequest, "ruleset is in use")
+					return
+				}
+			}
+			delete(s.rulesets, name)
+		}
+		json.NewEncoder(w).Encode(ruleset)
+	case strings.HasPrefix(name, project+"/releases/"):
+		release := s.releases[name]
+		if release == nil {
+			fail(http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		if r.Method == "PATCH" {
+			var body struct {
+				Release rulesRelease `json:"release"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			release.RulesetName = body.Release.RulesetName
+			release.UpdateTime = "2026-10-19T01:00:00Z"
+		}
+		json.NewEncoder(w).Encode(release)
+	default:
+		fail(http.StatusBadRequest, "unexpected request")
+	}
+}
+
+func newTestRulesServer() *testRulesServer {
+	return &testRulesServer{
+		rulesets: make(map[string]*rulesRuleset),
+		releases: make(map[string]*rulesRelease),
+	}
+}
+
+func testRulesetConfig(content string) map[string]interface{} {
+	return map[string]interface{}{
+		"file": []interface{}{
+			map[string]interface{}{"name": "firestore.rules", "content": content},
+		},
+	}
+}
+
+func testCreateRuleset(t *testing.T, meta interface{}, content string) *schema.ResourceData {
+	r := resourceFirebaseRulesRuleset()
+	diff, err := r.Diff(nil, testResourceConfig(t, testRulesetConfig(content)), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(nil, diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return r.Data(state)
+}
+
+func TestResourceFirebaseRulesRuleset_compileError(t *testing.T) {
+	fake := newTestRulesServer()
+	meta, closeServer := testAPIClient(t, fake, rulesEndpoint)
+	defer closeServer()
+
+	r := resourceFirebaseRulesRuleset()
+	_, err := r.Diff(nil, testResourceConfig(t, testRulesetConfig(
+		"service cloud.firestore {\n  match /{document=**} {\n    allow read: if !!;\n  }\n}")), meta)
+	if err == nil || !strings.Contains(err.Error(), "firestore.rules:3:20: Unexpected '!!'.") {
+		t.Fatalf("expected compile error with position, got: %v", err)
+	}
+	if len(fake.rulesets) != 0 {
+		t.Fatalf("ruleset created despite compile error")
+	}
+}
+
+func TestResourceFirebaseRulesRelease(t *testing.T) {
+	fake := newTestRulesServer()
+	meta, closeServer := testAPIClient(t, fake, rulesEndpoint)
+	defer closeServer()
+
+	first := testCreateRuleset(t, meta, "service cloud.firestore {}")
+	if first.Id() != fmt.Sprintf("projects/%s/rulesets/r1", testProjectID) || first.Get("name") != first.Id() {
+		t.Fatalf("incorrect ruleset: %s", first.Id())
+	}
+
+	// The database release exists already and is taken over
+	firestore := fmt.Sprintf("projects/%s/releases/cloud.firestore", testProjectID)
+	fake.releases[firestore] = &rulesRelease{Name: firestore, RulesetName: "projects/mock-project-id/rulesets/default"}
+
+	r := resourceFirebaseRulesRelease()
+	for _, name := range []string{"cloud.firestore", "firebase.storage/mock-project-id.appspot.com"} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"name":         name,
+			"ruleset_name": first.Id(),
+		})
+		if err := r.Create(d, meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if d.Id() != name || fake.releases[fmt.Sprintf("projects/%s/releases/%s", testProjectID, name)].RulesetName != first.Id() {
+			t.Fatalf("incorrect release: %s", d.Id())
+		}
+	}
+
+	second := testCreateRuleset(t, meta, "service cloud.firestore { match /a {} }")
+	third := testCreateRuleset(t, meta, "service cloud.firestore { match /b {} }")
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":         "cloud.firestore",
+		"ruleset_name": first.Id(),
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(d.State(), testResourceConfig(t, map[string]interface{}{
+		"name":            "cloud.firestore",
+		"ruleset_name":    third.Id(),
+		"retain_rulesets": 1,
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	state, err := r.Apply(d.State(), diff, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if state.Attributes["ruleset_name"] != third.Id() || fake.releases[firestore].RulesetName != third.Id() {
+		t.Fatalf("release not repointed: %s", fake.releases[firestore].RulesetName)
+	}
+
+	// The first ruleset is still used by the bucket release
+	if fake.rulesets[first.Id()] == nil || fake.rulesets[second.Id()] != nil || fake.rulesets[third.Id()] == nil {
+		t.Fatalf("incorrect garbage collection: %v", fake.rulesets)
+	}
+
+	// Rulesets in use are kept when destroyed
+	if err := resourceFirebaseRulesRuleset().Delete(first, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fake.rulesets[first.Id()] == nil {
+		t.Fatalf("ruleset in use was deleted")
+	}
+}
+
+func TestResourceFirebaseRulesRelease_retainPerService(t *testing.T) {
+	fake := newTestRulesServer()
+	meta, closeServer := testAPIClient(t, fake, rulesEndpoint)
+	defer closeServer()
+
+	firestore := testCreateRuleset(t, meta, "rules_version = '2';\nservice cloud.firestore {}")
+	storage := testCreateRuleset(t, meta, "service firebase.storage {}")
+
+	r := resourceFirebaseRulesRelease()
+	releases := map[string]*schema.ResourceData{}
+	for name, ruleset := range map[string]string{"cloud.firestore": firestore.Id(), "firebase.storage/mock-project-id.appspot.com": storage.Id()} {
+		releases[name] = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"name":         name,
+			"ruleset_name": ruleset,
+		})
+		if err := r.Create(releases[name], meta); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Both services get a new ruleset in the same apply, and another
+	// Firestore ruleset is pending
+	newStorage := testCreateRuleset(t, meta, "service firebase.storage { match /a {} }")
+	newFirestore := testCreateRuleset(t, meta, "service cloud.firestore { match /a {} }")
+	pending := testCreateRuleset(t, meta, "service cloud.firestore { match /b {} }")
+
+	diff, err := r.Diff(releases["cloud.firestore"].State(), testResourceConfig(t, map[string]interface{}{
+		"name":            "cloud.firestore",
+		"ruleset_name":    newFirestore.Id(),
+		"retain_rulesets": 1,
+	}), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(releases["cloud.firestore"].State(), diff, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if fake.rulesets[firestore.Id()] != nil {
+		t.Fatalf("old Firestore ruleset not deleted")
+	}
+	for _, ruleset := range []*schema.ResourceData{storage, newStorage, newFirestore, pending} {
+		if fake.rulesets[ruleset.Id()] == nil {
+			t.Fatalf("ruleset %s was deleted", ruleset.Id())
+		}
+	}
+}
+
+func TestResourceFirebaseRulesRelease_retainMixedPrecision(t *testing.T) {
+	fake := newTestRulesServer()
+	meta, closeServer := testAPIClient(t, fake, rulesEndpoint)
+	defer closeServer()
+
+	older := testCreateRuleset(t, meta, "service cloud.firestore {}")
+	old := testCreateRuleset(t, meta, "service cloud.firestore { match /a {} }")
+	released := testCreateRuleset(t, meta, "service cloud.firestore { match /b {} }")
+	pending := testCreateRuleset(t, meta, "service cloud.firestore { match /c {} }")
+
+	// The API trims trailing zeros of fractional seconds
+	fake.Lock()
+	fake.rulesets[older.Id()].CreateTime = "2026-10-19T00:00:05Z"
+	fake.rulesets[old.Id()].CreateTime = "2026-10-19T00:00:05.05Z"
+	fake.rulesets[released.Id()].CreateTime = "2026-10-19T00:00:05.100Z"
+	fake.rulesets[pending.Id()].CreateTime = "2026-10-19T00:00:05.123456789Z"
+	fake.Unlock()
+
+	r := resourceFirebaseRulesRelease()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":            "cloud.firestore",
+		"ruleset_name":    released.Id(),
+		"retain_rulesets": 1,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, ruleset := range []*schema.ResourceData{older, old} {
+		if fake.rulesets[ruleset.Id()] != nil {
+			t.Fatalf("old ruleset %s not deleted", ruleset.Id())
+		}
+	}
+	for _, ruleset := range []*schema.ResourceData{released, pending} {
+		if fake.rulesets[ruleset.Id()] == nil {
+			t.Fatalf("ruleset %s was deleted", ruleset.Id())
+		}
+	}
+}
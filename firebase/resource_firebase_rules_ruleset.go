@@ -0,0 +1,248 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const rulesEndpoint = "https://firebaserules.googleapis.com/v1"
+
+type rulesSource struct {
+	Files []rulesFile `json:"files"`
+}
+
+type rulesFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type rulesRuleset struct {
+	Name       string       `json:"name,omitempty"`
+	CreateTime string       `json:"createTime,omitempty"`
+	Source     *rulesSource `json:"source"`
+}
+
+type rulesIssue struct {
+	SourcePosition struct {
+		FileName string `json:"fileName"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"sourcePosition"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+}
+
+func resourceFirebaseRulesRuleset() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseRulesRulesetCreate,
+		Read:   resourceFirebaseRulesRulesetRead,
+		Delete: resourceFirebaseRulesRulesetDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceFirebaseRulesRulesetImportState,
+		},
+
+		CustomizeDiff: resourceFirebaseRulesRulesetCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"file": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"content": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func rulesProjectURL(project string) string {
+	return fmt.Sprintf("%s/projects/%s", rulesEndpoint, project)
+}
+
+func resourceFirebaseRulesRulesetCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Creating rules ruleset in project: %s", client.ProjectID)
+
+	var ruleset rulesRuleset
+	err = sendRequest(context.Background(), client.HTTP, "POST", rulesProjectURL(client.ProjectID)+"/rulesets",
+		&rulesRuleset{Source: expandRulesSource(d.Get("file"))}, &ruleset)
+	if err != nil {
+		return fmt.Errorf("Error creating rules ruleset: %s", err)
+	}
+
+	d.SetId(ruleset.Name)
+	log.Printf("[INFO] Rules ruleset: %s", d.Id())
+
+	return resourceFirebaseRulesRulesetRead(d, meta)
+}
+
+func resourceFirebaseRulesRulesetRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Reading rules ruleset: %s", d.Id())
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	var ruleset rulesRuleset
+	err = sendRequest(context.Background(), client.HTTP, "GET", rulesEndpoint+"/"+d.Id(), nil, &ruleset)
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Rules ruleset (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("Error reading rules ruleset (%s): %s", d.Id(), err)
+	}
+
+	if err := d.Set("file", flattenRulesSource(ruleset.Source)); err != nil {
+		return fmt.Errorf("Error setting file: %s", err)
+	}
+	d.Set("name", ruleset.Name)
+	d.Set("create_time", ruleset.CreateTime)
+
+	return nil
+}
+
+func resourceFirebaseRulesRulesetDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Replaced rulesets are destroyed before their releases are repointed,
+	// so rulesets still in use are kept until garbage collected
+	inUse, err := rulesRulesetsInUse(client)
+	if err != nil {
+		return err
+	}
+	if inUse[d.Id()] {
+		log.Printf("[INFO] Keeping rules ruleset in use by a release: %s", d.Id())
+		return nil
+	}
+
+	log.Printf("[INFO] Deleting rules ruleset: %s", d.Id())
+
+	err = sendRequest(context.Background(), client.HTTP, "DELETE", rulesEndpoint+"/"+d.Id(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("Error deleting rules ruleset (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceFirebaseRulesRulesetImportState accepts the ruleset's name,
+// projects/<project>/rulesets/<ruleset_id>.
+func resourceFirebaseRulesRulesetImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "rulesets" {
+		return nil, fmt.Errorf("Import ID %q should be projects/<project>/rulesets/<ruleset_id>", d.Id())
+	}
+	d.Set("project", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceFirebaseRulesRulesetCustomizeDiff compiles new sources with the
+// Rules API so that errors are reported at plan time.
+func resourceFirebaseRulesRulesetCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("file") {
+		log.Printf("[DEBUG] Skipping compilation, file is not known yet")
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("file") {
+		return nil
+	}
+
+	log.Printf("[INFO] Compiling rules ruleset")
+
+	client, err := meta.(Client).forProject(d.Get("project").(string))
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Issues []rulesIssue `json:"issues"`
+	}
+	err = sendRequest(context.Background(), client.HTTP, "POST", rulesProjectURL(client.ProjectID)+":test",
+		&rulesRuleset{Source: expandRulesSource(d.Get("file"))}, &result)
+	if err != nil {
+		return fmt.Errorf("Error compiling rules: %s", err)
+	}
+
+	return rulesIssuesError(result.Issues)
+}
+
+// rulesIssuesError returns the compile errors among issues with their
+// positions, and logs the warnings.
+func rulesIssuesError(issues []rulesIssue) error {
+	var errs *multierror.Error
+	for _, issue := range issues {
+		p := issue.SourcePosition
+		message := fmt.Sprintf("%s:%d:%d: %s", p.FileName, p.Line, p.Column, issue.Description)
+		if issue.Severity != "ERROR" {
+			log.Printf("[WARN] Rules %s: %s", strings.ToLower(issue.Severity), message)
+			continue
+		}
+		errs = multierror.Append(errs, fmt.Errorf("%s", message))
+	}
+	if errs != nil {
+		return fmt.Errorf("Error compiling rules: %s", errs)
+	}
+	return nil
+}
+
+func expandRulesSource(v interface{}) *rulesSource {
+	source := &rulesSource{}
+	for _, f := range v.([]interface{}) {
+		file := f.(map[string]interface{})
+		source.Files = append(source.Files, rulesFile{
+			Name:    file["name"].(string),
+			Content: file["content"].(string),
+		})
+	}
+	return source
+}
+
+func flattenRulesSource(source *rulesSource) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if source == nil {
+		return result
+	}
+	for _, f := range source.Files {
+		result = append(result, map[string]interface{}{
+			"name":    f.Name,
+			"content": f.Content,
+		})
+	}
+	return result
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"firebase.google.com/go/auth"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/structure"
 	"github.com/hashicorp/terraform/helper/validation"
 )
 
@@ -19,8 +23,9 @@ func resourceFirebaseUser() *schema.Resource {
 		Update: resourceFirebaseUserUpdate,
 		Delete: resourceFirebaseUserDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceFirebaseUserImportState,
 		},
+		CustomizeDiff: resourceFirebaseUserCustomizeDiff,
 
 		SchemaVersion: 0,
 		MigrateState:  resourceFirebaseUserMigrateState,
@@ -32,11 +37,18 @@ func resourceFirebaseUser() *schema.Resource {
 		},
 
 		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
 			"uid": {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringLenBetween(1, 128),
 			},
+			"tenant_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTenantID,
+			},
 			"display_name": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -49,10 +61,12 @@ func resourceFirebaseUser() *schema.Resource {
 				Default:  false,
 			},
 			"email": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      "",
-				ValidateFunc: validateEmail,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateEmail,
+				StateFunc:        normalizeEmail,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeEmail),
 			},
 			"email_verified": {
 				Type:     schema.TypeBool,
@@ -60,20 +74,126 @@ func resourceFirebaseUser() *schema.Resource {
 				Default:  false,
 			},
 			"password": {
-				Type:         schema.TypeString,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.StringLenBetween(6, 128),
+				ConflictsWith: []string{"generate_password"},
+			},
+			"generate_password": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"password"},
+			},
+			"password_length": {
+				Type:         schema.TypeInt,
 				Optional:     true,
-				ValidateFunc: validation.StringLenBetween(6, 128),
+				Default:      32,
+				ValidateFunc: validation.IntBetween(6, 128),
+			},
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
 			},
 			"phone_number": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validateE164PhoneNumber,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateE164PhoneNumber,
+				StateFunc:        normalizePhoneNumber,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizePhoneNumber),
 			},
 			"photo_url": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validateURL,
 			},
+			"custom_claims": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.ValidateJsonString,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+			},
+			"revoke_tokens_on_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"tokens_valid_after_millis": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			// Only the declared identities are tracked. Those linked since are
+			// left alone, and removed ones stay linked.
+			"federated_identity": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Set:      hashFederatedIdentity,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"provider_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateFederatedProviderID,
+						},
+						"federated_uid": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						// The profile of the identity when it is linked, the
+						// provider keeps it up to date afterwards
+						"email": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"photo_url": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"provider_user_info": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"provider_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"phone_number": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"photo_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -81,25 +201,47 @@ func resourceFirebaseUser() *schema.Resource {
 func resourceFirebaseUserCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Creating user uid: %s", d.Get("uid").(string))
 
-	client := meta.(Client).Auth
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
 	var u auth.UserToCreate
 
 	u.UID(d.Get("uid").(string))
-	u.Email(d.Get("email").(string))
+	u.Email(normalizeEmail(d.Get("email")))
 	u.DisplayName(d.Get("display_name").(string))
+	u.Disabled(d.Get("disabled").(bool))
 	u.EmailVerified(d.Get("email_verified").(bool))
-	u.PhoneNumber(d.Get("phone_number").(string))
+	u.PhoneNumber(normalizePhoneNumber(d.Get("phone_number")))
 	u.Password(d.Get("password").(string))
 	u.PhotoURL(d.Get("photo_url").(string))
 
-	userRecord, err := client.CreateUser(context.Background(), &u)
-	if err != nil {
-		return err
+	if d.Get("generate_password").(bool) {
+		password, err := generatePassword(d.Get("password_length").(int))
+		if err != nil {
+			return err
+		}
+		d.Set("generated_password", password)
+		u.Password(password)
 	}
-	log.Printf("[INFO] UID: %s", userRecord.UserInfo.UID)
 
-	// Store the resulting UID so we can look this up later
-	d.SetId(userRecord.UserInfo.UID)
+	if v, ok := d.GetOk("federated_identity"); ok {
+		// Federated identities can only be linked by importing the user
+		err := importFirebaseUser(client, d, v.(*schema.Set).List())
+		if err != nil {
+			return err
+		}
+		d.SetId(d.Get("uid").(string))
+	} else {
+		userRecord, err := client.CreateUser(context.Background(), &u)
+		if err != nil {
+			return err
+		}
+		log.Printf("[INFO] UID: %s", userRecord.UserInfo.UID)
+
+		// Store the resulting UID so we can look this up later
+		d.SetId(userRecord.UserInfo.UID)
+	}
 
 	log.Printf("[DEBUG] Waiting for user (%s) to become created", d.Id())
 
@@ -108,8 +250,8 @@ func resourceFirebaseUserCreate(d *schema.ResourceData, meta interface{}) error
 		Target:     []string{"created"},
 		Refresh:    userStateRefreshFunc(client, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutCreate),
-		Delay:      1 * time.Second,
-		MinTimeout: 2 * time.Second,
+		Delay:      pollDelay,
+		MinTimeout: pollMinTimeout,
 	}
 
 	_, err = stateConf.WaitForState()
@@ -118,14 +260,33 @@ func resourceFirebaseUserCreate(d *schema.ResourceData, meta interface{}) error
 			"Error waiting for user (%s) state to be created: %s", d.Id(), err)
 	}
 
+	if v, ok := d.GetOk("custom_claims"); ok {
+		claims, err := expandCustomClaims(v.(string))
+		if err != nil {
+			return err
+		}
+		err = client.SetCustomUserClaims(context.Background(), d.Id(), claims)
+		if err != nil {
+			return err
+		}
+	}
+
 	return resourceFirebaseUserUpdate(d, meta)
 }
 
 func resourceFirebaseUserRead(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Reading user uid: %s", d.Id())
-	client := meta.(Client).Auth
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	userRecord, err := client.GetUser(context.Background(), d.Id())
+	if auth.IsUserNotFound(err) {
+		log.Printf("[WARN] User %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
@@ -137,12 +298,40 @@ func resourceFirebaseUserRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("email_verified", userRecord.EmailVerified)
 	d.Set("phone_number", userRecord.UserInfo.PhoneNumber)
 	d.Set("photo_url", userRecord.UserInfo.PhotoURL)
+	d.Set("tokens_valid_after_millis", userRecord.TokensValidAfterMillis)
+
+	claims := ""
+	if len(userRecord.CustomClaims) > 0 {
+		claims, err = structure.FlattenJsonToString(userRecord.CustomClaims)
+		if err != nil {
+			return err
+		}
+	}
+	d.Set("custom_claims", claims)
+
+	err = d.Set("provider_user_info", flattenProviderUserInfo(userRecord.ProviderUserInfo))
+	if err != nil {
+		return err
+	}
+
+	// Declared identities keep their declared profile while still linked
+	linked := schema.NewSet(hashFederatedIdentity, flattenFederatedIdentities(userRecord.ProviderUserInfo))
+	identities := make([]interface{}, 0, linked.Len())
+	for _, v := range d.Get("federated_identity").(*schema.Set).List() {
+		if linked.Contains(v) {
+			identities = append(identities, v)
+		}
+	}
+	err = d.Set("federated_identity", identities)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func resourceFirebaseUserUpdate(d *schema.ResourceData, meta interface{}) error {
-	log.Printf("[DEBUG] update resource %+v\n", d)
+	logf("[DEBUG] Updating user resource %v", d)
 
 	if !d.IsNewResource() {
 		d.Partial(true)
@@ -161,6 +350,10 @@ func resourceFirebaseUserUpdate(d *schema.ResourceData, meta interface{}) error
 		changed = true
 		d.SetPartial("display_name")
 	}
+	if d.HasChange("disabled") && !d.IsNewResource() {
+		changed = true
+		d.SetPartial("disabled")
+	}
 	if d.HasChange("email_verified") && !d.IsNewResource() {
 		changed = true
 		d.SetPartial("email_verified")
@@ -177,23 +370,176 @@ func resourceFirebaseUserUpdate(d *schema.ResourceData, meta interface{}) error
 		changed = true
 		d.SetPartial("photo_url")
 	}
+	if (d.HasChange("generate_password") || d.HasChange("password_length") || d.HasChange("keepers")) && !d.IsNewResource() {
+		password := ""
+		if d.Get("generate_password").(bool) {
+			var err error
+			password, err = generatePassword(d.Get("password_length").(int))
+			if err != nil {
+				return err
+			}
+			changed = true
+		}
+		d.Set("generated_password", password)
+		d.SetPartial("generate_password")
+		d.SetPartial("password_length")
+		d.SetPartial("keepers")
+		d.SetPartial("generated_password")
+	}
+	if d.HasChange("custom_claims") && !d.IsNewResource() {
+		changed = true
+		d.SetPartial("custom_claims")
+	}
+	if d.HasChange("revoke_tokens_on_change") && !d.IsNewResource() {
+		d.SetPartial("revoke_tokens_on_change")
+	}
+	if d.HasChange("federated_identity") && !d.IsNewResource() {
+		d.SetPartial("federated_identity")
+	}
 
 	if changed {
 		log.Printf("[INFO] Updating uid: %s", d.Id())
-		client := meta.(Client).Auth
+		client, err := authClient(d, meta)
+		if err != nil {
+			return err
+		}
 		var u auth.UserToUpdate
 
-		u.Email(d.Get("email").(string))
+		u.Email(normalizeEmail(d.Get("email")))
 		u.DisplayName(d.Get("display_name").(string))
+		u.Disabled(d.Get("disabled").(bool))
 		u.EmailVerified(d.Get("email_verified").(bool))
-		u.PhoneNumber(d.Get("phone_number").(string))
+		u.PhoneNumber(normalizePhoneNumber(d.Get("phone_number")))
 		u.Password(d.Get("password").(string))
 		u.PhotoURL(d.Get("photo_url").(string))
 
-		_, err := client.UpdateUser(context.Background(), d.Id(), &u)
+		if d.Get("generate_password").(bool) {
+			u.Password(d.Get("generated_password").(string))
+		}
+
+		if d.HasChange("custom_claims") {
+			claims, err := expandCustomClaims(d.Get("custom_claims").(string))
+			if err != nil {
+				return err
+			}
+			u.CustomClaims(claims)
+		}
+
+		_, err = client.UpdateUser(context.Background(), d.Id(), &u)
 		if err != nil {
 			return err
 		}
+
+		if d.Get("revoke_tokens_on_change").(bool) && (d.HasChange("custom_claims") ||
+			d.HasChange("password") || d.HasChange("disabled") ||
+			d.HasChange("generate_password") || d.HasChange("password_length") || d.HasChange("keepers")) {
+			log.Printf("[INFO] Revoking refresh tokens for uid: %s", d.Id())
+			err = client.RevokeRefreshTokens(context.Background(), d.Id())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return resourceFirebaseUserRead(d, meta)
+}
+
+// userClaims records which user claims an email or phone number. It lives
+// as long as the provider configuration, so it spans a single plan.
+type userClaims struct {
+	sync.Mutex
+	uids map[string]string
+}
+
+// claim records uid as the owner of key unless another user claimed it
+// first, and returns the owner.
+func (c *userClaims) claim(key, uid string) string {
+	c.Lock()
+	defer c.Unlock()
+
+	if owner, ok := c.uids[key]; ok {
+		return owner
+	}
+	c.uids[key] = uid
+	return uid
+}
+
+// release forgets the claim of uid on key, e.g. once the user's email
+// changes, so that another user can claim it.
+func (c *userClaims) release(key, uid string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.uids[key] == uid {
+		delete(c.uids, key)
+	}
+}
+
+// resourceFirebaseUserCustomizeDiff plans the rotation of the generated
+// password and the replacement of users gaining federated identities, and
+// fails the plan when two users of the same project and tenant
+// claim the same email or phone number, which the backend would otherwise
+// reject halfway through the apply.
+func resourceFirebaseUserCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && (d.HasChange("generate_password") || d.HasChange("password_length") || d.HasChange("keepers")) {
+		if err := d.SetNewComputed("generated_password"); err != nil {
+			return err
+		}
+	}
+
+	// Identities can only be linked by importing the user, which replaces it
+	if d.Id() != "" && d.NewValueKnown("federated_identity") && d.HasChange("federated_identity") {
+		o, n := d.GetChange("federated_identity")
+		if n.(*schema.Set).Difference(o.(*schema.Set)).Len() > 0 {
+			if err := d.ForceNew("federated_identity"); err != nil {
+				return err
+			}
+		}
+	}
+
+	client := meta.(Client)
+	if client.userClaims == nil || !d.NewValueKnown("uid") || !d.NewValueKnown("tenant_id") {
+		return nil
+	}
+
+	// The project of a new resource is computed from the provider's unless
+	// it is set
+	project := d.Get("project").(string)
+	if project == "" {
+		project = client.ProjectID
+	}
+	uid := d.Get("uid").(string)
+	claimKey := func(k, value string) string {
+		return fmt.Sprintf("%s/%s/%s/%s", project, d.Get("tenant_id"), k, value)
+	}
+
+	identifiers := []struct {
+		key       string
+		normalize schema.SchemaStateFunc
+	}{
+		{"email", normalizeEmail},
+		{"phone_number", normalizePhoneNumber},
+	}
+	for _, id := range identifiers {
+		k := id.key
+		if !d.NewValueKnown(k) {
+			continue
+		}
+
+		// The user gives up its current value, which others may claim in
+		// the same plan
+		if old, _ := d.GetChange(k); d.HasChange(k) {
+			if value := id.normalize(old); value != "" {
+				client.userClaims.release(claimKey(k, value), uid)
+			}
+		}
+
+		value := id.normalize(d.Get(k))
+		if value == "" {
+			continue
+		}
+		if owner := client.userClaims.claim(claimKey(k, value), uid); owner != uid {
+			return fmt.Errorf("%s %q is also claimed by the firebase_user with uid %q", k, value, owner)
+		}
 	}
 	return nil
 }
@@ -201,9 +547,12 @@ func resourceFirebaseUserUpdate(d *schema.ResourceData, meta interface{}) error
 func resourceFirebaseUserDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] Deleting uid: %s", d.Id())
 
-	client := meta.(Client).Auth
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
 
-	err := client.DeleteUser(context.Background(), d.Id())
+	err = client.DeleteUser(context.Background(), d.Id())
 	if err != nil {
 		return err
 	}
@@ -215,8 +564,8 @@ func resourceFirebaseUserDelete(d *schema.ResourceData, meta interface{}) error
 		Target:     []string{"deleted"},
 		Refresh:    userStateRefreshFunc(client, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutDelete),
-		Delay:      1 * time.Second,
-		MinTimeout: 2 * time.Second,
+		Delay:      pollDelay,
+		MinTimeout: pollMinTimeout,
 	}
 
 	_, err = stateConf.WaitForState()
@@ -228,11 +577,105 @@ func resourceFirebaseUserDelete(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
+// resourceFirebaseUserImportState resolves an import ID of the form
+// "email:<email>", "phone:<phone number>" or a bare UID to the user's UID.
+// Users of a tenant are addressed as "tenants/<tenant_id>/<id>", users of
+// another project are prefixed with "projects/<project>/".
+func resourceFirebaseUserImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	if strings.HasPrefix(id, "projects/") {
+		parts := strings.SplitN(strings.TrimPrefix(id, "projects/"), "/", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Import ID %q should be of the form projects/<project>/<id>", id)
+		}
+		d.Set("project", parts[0])
+		id = parts[1]
+	}
+	if strings.HasPrefix(id, "tenants/") {
+		parts := strings.SplitN(strings.TrimPrefix(id, "tenants/"), "/", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Import ID %q should be of the form tenants/<tenant_id>/<id>", id)
+		}
+		d.Set("tenant_id", parts[0])
+		id = parts[1]
+	}
+
+	client, err := authClient(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+
+	var userRecord *auth.UserRecord
+	switch {
+	case strings.HasPrefix(id, "email:"):
+		email := strings.TrimPrefix(id, "email:")
+		logf("[INFO] Importing user by email: %s", email)
+		if email == "" {
+			return nil, fmt.Errorf("Import ID %q is missing the email address", id)
+		}
+		userRecord, err = client.GetUserByEmail(ctx, email)
+	case strings.HasPrefix(id, "phone:"):
+		phone := strings.TrimPrefix(id, "phone:")
+		logf("[INFO] Importing user by phone number: %s", phone)
+		if phone == "" {
+			return nil, fmt.Errorf("Import ID %q is missing the phone number", id)
+		}
+		userRecord, err = client.GetUserByPhoneNumber(ctx, phone)
+	default:
+		logf("[INFO] Importing user by uid: %s", id)
+		userRecord, err = client.GetUser(ctx, id)
+
+		// A bare ID that looks like an email or phone number may refer to
+		// a different user than the one with that UID.
+		var other *auth.UserRecord
+		var prefix string
+		if strings.Contains(id, "@") {
+			prefix = "email:"
+			other, _ = client.GetUserByEmail(ctx, id)
+		} else if strings.HasPrefix(id, "+") {
+			prefix = "phone:"
+			other, _ = client.GetUserByPhoneNumber(ctx, id)
+		}
+		switch {
+		case err == nil && other != nil && other.UserInfo.UID != userRecord.UserInfo.UID:
+			return nil, fmt.Errorf(
+				"Import ID %q is ambiguous: it is the uid of user %q and belongs to user %q; import %q or \"%s%s\" instead",
+				id, userRecord.UserInfo.UID, other.UserInfo.UID, other.UserInfo.UID, prefix, id)
+		case auth.IsUserNotFound(err) && other != nil:
+			return nil, fmt.Errorf("No user found with uid %q; to import user %q use \"%s%s\"",
+				id, other.UserInfo.UID, prefix, id)
+		}
+	}
+	if err != nil {
+		if auth.IsUserNotFound(err) {
+			return nil, fmt.Errorf("No user found matching %q", id)
+		}
+		return nil, fmt.Errorf("Error looking up user (%s): %s", id, err)
+	}
+
+	d.SetId(userRecord.UserInfo.UID)
+	if err := resourceFirebaseUserRead(d, meta); err != nil {
+		return nil, err
+	}
+	// Nothing is declared yet, all linked identities are taken over
+	if err := d.Set("federated_identity", flattenFederatedIdentities(userRecord.ProviderUserInfo)); err != nil {
+		return nil, err
+	}
+
+	// Arguments that only exist in configuration take their defaults
+	d.Set("generate_password", false)
+	d.Set("password_length", 32)
+	d.Set("revoke_tokens_on_change", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func userStateRefreshFunc(client *auth.Client, uid string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		log.Printf("[DEBUG] Checking user (%s) state\n", uid)
 		userRecord, err := client.GetUser(context.Background(), uid)
-		log.Printf("[DEBUG] UserRecord: %+v)\n", userRecord)
+		logf("[DEBUG] UserRecord: %v", userRecord)
 		if err != nil {
 			log.Printf("[DEBUG] The user (%s) doesn't exist state (deleted)\n", uid)
 			return auth.UserInfo{}, "deleted", nil
@@ -241,3 +684,116 @@ func userStateRefreshFunc(client *auth.Client, uid string) resource.StateRefresh
 		return userRecord, "created", nil
 	}
 }
+
+// importFirebaseUser creates the user through ImportUsers, which is the only
+// way to link federated identities without a client-side sign in.
+func importFirebaseUser(client *auth.Client, d *schema.ResourceData, identities []interface{}) error {
+	// ImportUsers overwrites any user with the same UID, unlike CreateUser
+	uid := d.Get("uid").(string)
+	_, err := client.GetUser(context.Background(), uid)
+	if err == nil {
+		return fmt.Errorf("User (%s) already exists, import it instead", uid)
+	}
+	if !auth.IsUserNotFound(err) {
+		return fmt.Errorf("Error looking up user (%s): %s", uid, err)
+	}
+
+	var u auth.UserToImport
+
+	u.UID(uid)
+	u.Email(normalizeEmail(d.Get("email")))
+	u.DisplayName(d.Get("display_name").(string))
+	u.Disabled(d.Get("disabled").(bool))
+	u.EmailVerified(d.Get("email_verified").(bool))
+	u.PhoneNumber(normalizePhoneNumber(d.Get("phone_number")))
+	u.PhotoURL(d.Get("photo_url").(string))
+	u.ProviderData(expandFederatedIdentities(identities))
+
+	result, err := client.ImportUsers(context.Background(), []*auth.UserToImport{&u})
+	if err != nil {
+		return err
+	}
+	if result.FailureCount > 0 {
+		return fmt.Errorf("Error importing user (%s): %s", uid, result.Errors[0].Reason)
+	}
+
+	// Passwords can only be imported as hashes, so set the plain text afterwards
+	password := d.Get("password").(string)
+	if d.Get("generate_password").(bool) {
+		password = d.Get("generated_password").(string)
+	}
+	if password != "" {
+		var update auth.UserToUpdate
+		update.Password(password)
+		_, err = client.UpdateUser(context.Background(), uid, &update)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expandFederatedIdentities(identities []interface{}) []*auth.UserProvider {
+	providers := make([]*auth.UserProvider, 0, len(identities))
+	for _, v := range identities {
+		identity := v.(map[string]interface{})
+		providers = append(providers, &auth.UserProvider{
+			ProviderID:  identity["provider_id"].(string),
+			UID:         identity["federated_uid"].(string),
+			Email:       identity["email"].(string),
+			DisplayName: identity["display_name"].(string),
+			PhotoURL:    identity["photo_url"].(string),
+		})
+	}
+	return providers
+}
+
+// flattenFederatedIdentities returns the identities that were not created by
+// the password and phone sign-in methods.
+func flattenFederatedIdentities(infos []*auth.UserInfo) []interface{} {
+	identities := make([]interface{}, 0, len(infos))
+	for _, info := range infos {
+		if info.ProviderID == "password" || info.ProviderID == "phone" {
+			continue
+		}
+		identities = append(identities, map[string]interface{}{
+			"provider_id":   info.ProviderID,
+			"federated_uid": info.UID,
+			"email":         info.Email,
+			"display_name":  info.DisplayName,
+			"photo_url":     info.PhotoURL,
+		})
+	}
+	return identities
+}
+
+// hashFederatedIdentity identifies a federated identity by its provider and
+// federated UID, regardless of its profile.
+func hashFederatedIdentity(v interface{}) int {
+	identity := v.(map[string]interface{})
+	return hashcode.String(identity["provider_id"].(string) + "/" + identity["federated_uid"].(string))
+}
+
+func flattenProviderUserInfo(infos []*auth.UserInfo) []interface{} {
+	result := make([]interface{}, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, map[string]interface{}{
+			"provider_id":  info.ProviderID,
+			"uid":          info.UID,
+			"email":        info.Email,
+			"display_name": info.DisplayName,
+			"phone_number": info.PhoneNumber,
+			"photo_url":    info.PhotoURL,
+		})
+	}
+	return result
+}
+
+// expandCustomClaims decodes the custom_claims JSON document. An empty
+// document yields nil claims, which removes all claims from the user.
+func expandCustomClaims(v string) (map[string]interface{}, error) {
+	if v == "" {
+		return nil, nil
+	}
+	return structure.ExpandJsonFromString(v)
+}
@@ -0,0 +1,175 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+const identityToolkitEndpoint = "https://www.googleapis.com/identitytoolkit/v3/relyingparty"
+
+type oobConfirmationCodeRequest struct {
+	RequestType           string `json:"requestType"`
+	Email                 string `json:"email"`
+	ReturnOobLink         bool   `json:"returnOobLink"`
+	ContinueURL           string `json:"continueUrl,omitempty"`
+	CanHandleCodeInApp    bool   `json:"canHandleCodeInApp,omitempty"`
+	IOSBundleID           string `json:"iOSBundleId,omitempty"`
+	AndroidPackageName    string `json:"androidPackageName,omitempty"`
+	AndroidInstallApp     bool   `json:"androidInstallApp,omitempty"`
+	AndroidMinimumVersion string `json:"androidMinimumVersion,omitempty"`
+	TenantID              string `json:"tenantId,omitempty"`
+}
+
+type oobConfirmationCodeResponse struct {
+	Email   string `json:"email"`
+	OobCode string `json:"oobCode"`
+	OobLink string `json:"oobLink"`
+}
+
+func resourceFirebaseUserActionLink() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseUserActionLinkCreate,
+		Read:   resourceFirebaseUserActionLinkRead,
+		Delete: resourceFirebaseUserActionLinkDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"email": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateEmail,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"VERIFY_EMAIL",
+					"PASSWORD_RESET",
+					"EMAIL_SIGNIN",
+				}, false),
+			},
+			"continue_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateURL,
+			},
+			"handle_code_in_app": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"ios_bundle_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"android_package_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"android_install_app": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"android_minimum_version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"tenant_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTenantID,
+			},
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"link": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseUserActionLinkCreate(d *schema.ResourceData, meta interface{}) error {
+	email := d.Get("email").(string)
+	logf("[INFO] Creating %s link for: %s", d.Get("type").(string), email)
+
+	req := &oobConfirmationCodeRequest{
+		RequestType:           d.Get("type").(string),
+		Email:                 email,
+		ReturnOobLink:         true,
+		ContinueURL:           d.Get("continue_url").(string),
+		CanHandleCodeInApp:    d.Get("handle_code_in_app").(bool),
+		IOSBundleID:           d.Get("ios_bundle_id").(string),
+		AndroidPackageName:    d.Get("android_package_name").(string),
+		AndroidInstallApp:     d.Get("android_install_app").(bool),
+		AndroidMinimumVersion: d.Get("android_minimum_version").(string),
+		TenantID:              d.Get("tenant_id").(string),
+	}
+
+	if req.ContinueURL == "" {
+		if req.RequestType == "EMAIL_SIGNIN" {
+			return fmt.Errorf("continue_url is required for EMAIL_SIGNIN links")
+		}
+		if req.CanHandleCodeInApp || req.IOSBundleID != "" || req.AndroidPackageName != "" {
+			return fmt.Errorf("continue_url is required when app settings are specified")
+		}
+	}
+	if req.AndroidPackageName == "" && (req.AndroidInstallApp || req.AndroidMinimumVersion != "") {
+		return fmt.Errorf("android_package_name is required when android settings are specified")
+	}
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// Only the Identity Platform endpoint accepts a tenant
+	url := identityToolkitEndpoint + "/getOobConfirmationCode"
+	if req.TenantID != "" {
+		url = fmt.Sprintf("%s/projects/%s/accounts:sendOobCode", identityToolkitV1Endpoint, client.ProjectID)
+	}
+
+	var res oobConfirmationCodeResponse
+	err = sendRequest(context.Background(), client.HTTP, "POST", url, req, &res)
+	if err != nil {
+		return fmt.Errorf("Error generating %s link for %s: %s", req.RequestType, email, err)
+	}
+	if res.OobLink == "" {
+		return fmt.Errorf("Error generating %s link for %s: no link returned", req.RequestType, email)
+	}
+
+	d.SetId(resource.UniqueId())
+	d.Set("link", res.OobLink)
+
+	return resourceFirebaseUserActionLinkRead(d, meta)
+}
+
+func resourceFirebaseUserActionLinkRead(d *schema.ResourceData, meta interface{}) error {
+	// Links are generated once and cannot be read back from the API
+	return nil
+}
+
+func resourceFirebaseUserActionLinkDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Forgetting action link: %s", d.Id())
+	d.SetId("")
+	return nil
+}
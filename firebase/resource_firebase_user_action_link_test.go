@@ -0,0 +1,57 @@
+package firebase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+
+	"firebase.google.com/go/auth"
+)
+
+func TestAccFirebaseUserActionLink_basic(t *testing.T) {
+	var v auth.UserRecord
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserActionLinkConfig(testUser),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					resource.TestCheckResourceAttrSet("firebase_user_action_link.verify", "link"),
+					resource.TestCheckResourceAttrSet("firebase_user_action_link.reset", "link"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserActionLinkConfig(u *auth.UserRecord) string {
+	return fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid      = "%s"
+	email    = "%s"
+	password = "password123"
+}
+
+resource "firebase_user_action_link" "verify" {
+	email        = "${firebase_user.john_doe.email}"
+	type         = "VERIFY_EMAIL"
+	continue_url = "https://www.example.com/welcome"
+}
+
+resource "firebase_user_action_link" "reset" {
+	email                = "${firebase_user.john_doe.email}"
+	type                 = "PASSWORD_RESET"
+	continue_url         = "https://www.example.com/login"
+	handle_code_in_app   = true
+	ios_bundle_id        = "com.example.ios"
+	android_package_name = "com.example.android"
+	android_install_app  = true
+}
+`, u.UserInfo.UID,
+		u.UserInfo.Email)
+}
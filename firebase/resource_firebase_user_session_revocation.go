@@ -0,0 +1,94 @@
+package firebase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"firebase.google.com/go/auth"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceFirebaseUserSessionRevocation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseUserSessionRevocationCreate,
+		Read:   resourceFirebaseUserSessionRevocationRead,
+		Delete: resourceFirebaseUserSessionRevocationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"uid": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 128),
+			},
+			"tenant_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTenantID,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"tokens_valid_after_millis": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceFirebaseUserSessionRevocationCreate(d *schema.ResourceData, meta interface{}) error {
+	uid := d.Get("uid").(string)
+	log.Printf("[INFO] Revoking refresh tokens for uid: %s", uid)
+
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = client.RevokeRefreshTokens(context.Background(), uid)
+	if err != nil {
+		return fmt.Errorf("Error revoking refresh tokens for user (%s): %s", uid, err)
+	}
+
+	d.SetId(resource.PrefixedUniqueId(uid + "-"))
+
+	return resourceFirebaseUserSessionRevocationRead(d, meta)
+}
+
+func resourceFirebaseUserSessionRevocationRead(d *schema.ResourceData, meta interface{}) error {
+	uid := d.Get("uid").(string)
+	log.Printf("[INFO] Reading session revocation for uid: %s", uid)
+
+	client, err := authClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	userRecord, err := client.GetUser(context.Background(), uid)
+	if err != nil {
+		if auth.IsUserNotFound(err) {
+			log.Printf("[WARN] User (%s) not found, removing session revocation from state", uid)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("tokens_valid_after_millis", userRecord.TokensValidAfterMillis)
+
+	return nil
+}
+
+func resourceFirebaseUserSessionRevocationDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Forgetting session revocation: %s", d.Id())
+	d.SetId("")
+	return nil
+}
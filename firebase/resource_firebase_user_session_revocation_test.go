@@ -0,0 +1,80 @@
+package firebase
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+
+	"firebase.google.com/go/auth"
+)
+
+func TestAccFirebaseUserSessionRevocation_basic(t *testing.T) {
+	var v auth.UserRecord
+	var validAfter int64
+
+	testCheckRevoked := func(rotated bool) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			rs := s.RootModule().Resources["firebase_user_session_revocation.john_doe"]
+			millis, err := strconv.ParseInt(rs.Primary.Attributes["tokens_valid_after_millis"], 10, 64)
+			if err != nil {
+				return err
+			}
+			if millis == 0 {
+				return fmt.Errorf("tokens_valid_after_millis is not set")
+			}
+			if rotated && millis < validAfter {
+				return fmt.Errorf("tokens_valid_after_millis went backwards: %d < %d", millis, validAfter)
+			}
+			validAfter = millis
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserSessionRevocationConfig(testUser, "admin"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					testCheckRevoked(false),
+				),
+			},
+			{
+				Config: testAccUserSessionRevocationConfig(testUser, "viewer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					testCheckRevoked(true),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserSessionRevocationConfig(u *auth.UserRecord, role string) string {
+	return fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid                     = "%s"
+	email                   = "%s"
+	password                = "password123"
+	custom_claims           = "{\"role\":\"%s\"}"
+	revoke_tokens_on_change = true
+}
+
+resource "firebase_user_session_revocation" "john_doe" {
+	uid = "${firebase_user.john_doe.id}"
+
+	triggers = {
+		role = "%s"
+	}
+}
+`, u.UserInfo.UID,
+		u.UserInfo.Email,
+		role,
+		role)
+}
@@ -2,14 +2,20 @@ package firebase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/eliaszs/terraform-provider-firebase/firebase/internal/authtest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 
 	"firebase.google.com/go/auth"
+	identitytoolkit "google.golang.org/api/identitytoolkit/v3"
 )
 
 const defaultProviderID = "firebase"
@@ -88,6 +94,109 @@ func TestAccFirebaseUser_basic(t *testing.T) {
 	})
 }
 
+func TestResourceFirebaseUser_lifecycle(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	providers, provider, cleanup := testUnitProviders(t, srv)
+	defer cleanup()
+
+	updated := *testUser
+	updated.UserInfo = &auth.UserInfo{}
+	*updated.UserInfo = *testUser.UserInfo
+	updated.UserInfo.DisplayName = "Johnny Doe"
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccCheckUserDestroyWithProvider(s, provider)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig(testUser),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExistsWithProvider("firebase_user.john_doe", nil, func() *schema.Provider { return provider }),
+					resource.TestCheckResourceAttr("firebase_user.john_doe", "display_name", testUser.UserInfo.DisplayName),
+				),
+			},
+			{
+				Config: testAccUserConfig(&updated),
+				Check: func(*terraform.State) error {
+					u, ok := srv.User(testUser.UserInfo.UID)
+					if !ok || u.DisplayName != updated.UserInfo.DisplayName {
+						return fmt.Errorf("display_name was not updated: %q", u.DisplayName)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:            "firebase_user.john_doe",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "generate_password", "password_length", "revoke_tokens_on_change"},
+			},
+			{
+				// A user deleted outside of Terraform is created again
+				PreConfig: func() { srv.DeleteUser(testUser.UserInfo.UID) },
+				Config:    testAccUserConfig(&updated),
+				Check: func(*terraform.State) error {
+					if _, ok := srv.User(testUser.UserInfo.UID); !ok {
+						return fmt.Errorf("user was not recreated")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestResourceFirebaseUser_normalization(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	providers, provider, cleanup := testUnitProviders(t, srv)
+	defer cleanup()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccCheckUserDestroyWithProvider(s, provider)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid          = "%s"
+	display_name = "%s"
+	email        = "John.Doe@Example.com"
+	phone_number = "+1 (415) 555-2671"
+	photo_url    = "%s"
+}
+`, testUser.UserInfo.UID, testUser.UserInfo.DisplayName, testUser.UserInfo.PhotoURL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("firebase_user.john_doe", "email", "john.doe@example.com"),
+					resource.TestCheckResourceAttr("firebase_user.john_doe", "phone_number", "+14155552671"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid          = "%s"
+	display_name = "%s"
+	email        = "john.doe@example.com"
+	phone_number = "+14155552671"
+	photo_url    = "%s"
+}
+
+resource "firebase_user" "johnny_doe" {
+	uid   = "johnny"
+	email = "John.Doe@example.com"
+}
+`, testUser.UserInfo.UID, testUser.UserInfo.DisplayName, testUser.UserInfo.PhotoURL),
+				ExpectError: regexp.MustCompile(`email "john.doe@example.com" is also claimed by the firebase_user with uid "(johnny|` + testUser.UserInfo.UID + `)"`),
+			},
+		},
+	})
+}
+
 func testAccCheckUserDestroy(s *terraform.State) error {
 	return testAccCheckUserDestroyWithProvider(s, testAccProvider)
 }
@@ -151,3 +260,484 @@ resource "firebase_user" "john_doe" {
 		u.UserInfo.PhoneNumber,
 		u.UserInfo.PhotoURL)
 }
+
+func TestAccFirebaseUser_generatedPassword(t *testing.T) {
+	var v auth.UserRecord
+	var password string
+
+	testCheckRotated := func() resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			rs := s.RootModule().Resources["firebase_user.john_doe"]
+			if rs.Primary.Attributes["generated_password"] == password {
+				return fmt.Errorf("generated_password was not rotated")
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserGeneratedPasswordConfig(testUser, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					resource.TestCheckResourceAttrSet("firebase_user.john_doe", "generated_password"),
+					func(s *terraform.State) error {
+						password = s.RootModule().Resources["firebase_user.john_doe"].Primary.Attributes["generated_password"]
+						if len(password) != 40 {
+							return fmt.Errorf("incorrect generated_password length: %d", len(password))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccUserGeneratedPasswordConfig(testUser, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					testCheckRotated(),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserGeneratedPasswordConfig(u *auth.UserRecord, rotation string) string {
+	return fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid               = "%s"
+	email             = "%s"
+	generate_password = true
+	password_length   = 40
+
+	keepers = {
+		rotation = "%s"
+	}
+}
+`, u.UserInfo.UID,
+		u.UserInfo.Email,
+		rotation)
+}
+
+func TestAccFirebaseUser_federatedIdentity(t *testing.T) {
+	var v auth.UserRecord
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserFederatedIdentityConfig(testUser),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("firebase_user.john_doe", &v),
+					resource.TestCheckResourceAttr("firebase_user.john_doe", "federated_identity.#", "1"),
+					resource.TestCheckResourceAttrSet("firebase_user.john_doe", "provider_user_info.#"),
+				),
+			},
+			{
+				ResourceName:            "firebase_user.john_doe",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "generate_password", "password_length", "revoke_tokens_on_change"},
+			},
+		},
+	})
+}
+
+func testAccUserFederatedIdentityConfig(u *auth.UserRecord) string {
+	return fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid            = "%s"
+	display_name   = "%s"
+	email          = "%s"
+	email_verified = true
+	password       = "password123"
+
+	federated_identity {
+		provider_id   = "google.com"
+		federated_uid = "108123456789012345678"
+		email         = "%s"
+		display_name  = "%s"
+	}
+}
+`, u.UserInfo.UID,
+		u.UserInfo.DisplayName,
+		u.UserInfo.Email,
+		u.UserInfo.Email,
+		u.UserInfo.DisplayName)
+}
+
+func TestResourceFirebaseUserImportState(t *testing.T) {
+	users := []map[string]interface{}{
+		{"localId": testUser.UserInfo.UID, "email": testUser.UserInfo.Email, "phoneNumber": testUser.UserInfo.PhoneNumber},
+		{"localId": "jane.doe@example.com"},
+		{"localId": "jane", "email": "jane.doe@example.com"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/getAccountInfo", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string][]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var found []map[string]interface{}
+		for _, u := range users {
+			for k, values := range req {
+				if len(values) == 1 && u[k] == values[0] {
+					found = append(found, u)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"users": found})
+	})
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		identityToolkitEndpoint: srv.URL,
+	})
+
+	r := resourceFirebaseUser()
+	importState := func(id string) (*schema.ResourceData, error) {
+		d := r.Data(nil)
+		d.SetId(id)
+		states, err := r.Importer.State(d, meta)
+		if err != nil {
+			return nil, err
+		}
+		return states[0], nil
+	}
+
+	for _, id := range []string{
+		testUser.UserInfo.UID,
+		"email:" + testUser.UserInfo.Email,
+		"phone:" + testUser.UserInfo.PhoneNumber,
+	} {
+		d, err := importState(id)
+		if err != nil {
+			t.Fatalf("%s: err: %s", id, err)
+		}
+		if d.Id() != testUser.UserInfo.UID || d.Get("email") != testUser.UserInfo.Email {
+			t.Fatalf("%s: incorrect user: %s %s", id, d.Id(), d.Get("email"))
+		}
+		if d.Get("password_length") != 32 {
+			t.Fatalf("%s: password_length was not defaulted", id)
+		}
+	}
+
+	cases := map[string]string{
+		"email:nobody@example.com": "No user found",
+		"phone:":                   "missing the phone number",
+		"jane.doe@example.com":     "ambiguous",
+		"+15555550100":             "No user found",
+	}
+	for id, expected := range cases {
+		if _, err := importState(id); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: expected error containing %q, got: %v", id, expected, err)
+		}
+	}
+
+	users = users[2:]
+	if _, err := importState("jane.doe@example.com"); err == nil || !strings.Contains(err.Error(), `use "email:jane.doe@example.com"`) {
+		t.Fatalf("expected email prefix hint, got: %v", err)
+	}
+}
+
+func TestResourceFirebaseUserCustomizeDiff_generatedPassword(t *testing.T) {
+	r := resourceFirebaseUser()
+	state := &terraform.InstanceState{
+		ID: testUser.UserInfo.UID,
+		Attributes: map[string]string{
+			"uid":                  testUser.UserInfo.UID,
+			"generate_password":    "true",
+			"password_length":      "32",
+			"keepers.%":            "1",
+			"keepers.rotation":     "first",
+			"generated_password":   "old-password",
+			"project":              testProjectID,
+			"federated_identity.#": "0",
+		},
+	}
+	for rotation, rotated := range map[string]bool{"first": false, "second": true} {
+		diff, err := r.Diff(state, testResourceConfig(t, map[string]interface{}{
+			"uid":               testUser.UserInfo.UID,
+			"generate_password": true,
+			"keepers":           map[string]interface{}{"rotation": rotation},
+		}), Client{})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var computed bool
+		if diff != nil && diff.Attributes["generated_password"] != nil {
+			computed = diff.Attributes["generated_password"].NewComputed
+		}
+		if computed != rotated {
+			t.Fatalf("%s: generated_password new computed %t", rotation, computed)
+		}
+	}
+}
+
+func TestResourceFirebaseUser_revokeOnRotation(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	providers, provider, cleanup := testUnitProviders(t, srv)
+	defer cleanup()
+
+	config := func(rotation string) string {
+		return fmt.Sprintf(`
+resource "firebase_user" "john_doe" {
+	uid                     = "%s"
+	display_name            = "%s"
+	email                   = "%s"
+	phone_number            = "%s"
+	photo_url               = "%s"
+	generate_password       = true
+	revoke_tokens_on_change = true
+
+	keepers = {
+		rotation = "%s"
+	}
+}
+`, testUser.UserInfo.UID, testUser.UserInfo.DisplayName, testUser.UserInfo.Email,
+			testUser.UserInfo.PhoneNumber, testUser.UserInfo.PhotoURL, rotation)
+	}
+
+	var password string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccCheckUserDestroyWithProvider(s, provider)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("first"),
+				Check: func(s *terraform.State) error {
+					password = s.RootModule().Resources["firebase_user.john_doe"].Primary.Attributes["generated_password"]
+					if n := srv.Revocations(testUser.UserInfo.UID); n != 0 {
+						return fmt.Errorf("refresh tokens revoked on create: %d", n)
+					}
+					return nil
+				},
+			},
+			{
+				Config: config("second"),
+				Check: func(s *terraform.State) error {
+					if s.RootModule().Resources["firebase_user.john_doe"].Primary.Attributes["generated_password"] == password {
+						return fmt.Errorf("generated_password was not rotated")
+					}
+					if n := srv.Revocations(testUser.UserInfo.UID); n != 1 {
+						return fmt.Errorf("refresh tokens revoked %d times on rotation", n)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestResourceFirebaseUser_federatedIdentityExistingUID(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	meta := testClient(t, srv.Server, srv.Endpoints())
+
+	r := resourceFirebaseUser()
+	federated := func(uid, phone string) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+			"uid":          uid,
+			"display_name": "Jane Doe",
+			"email":        uid + "@example.com",
+			"phone_number": phone,
+			"photo_url":    testUser.UserInfo.PhotoURL,
+			"federated_identity": []interface{}{
+				map[string]interface{}{"provider_id": "google.com", "federated_uid": "108123456789012345678"},
+			},
+		})
+	}
+
+	d := federated("jane", "+14155550100")
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("federated_identity.#") != 1 {
+		t.Fatalf("federated identity not linked: %v", d.Get("federated_identity"))
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"uid":          testUser.UserInfo.UID,
+		"display_name": testUser.UserInfo.DisplayName,
+		"email":        testUser.UserInfo.Email,
+		"phone_number": testUser.UserInfo.PhoneNumber,
+		"photo_url":    testUser.UserInfo.PhotoURL,
+		"password":     "password123",
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Importing would silently overwrite the existing user
+	d = federated(testUser.UserInfo.UID, "+14155550101")
+	if err := r.Create(d, meta); err == nil || !strings.Contains(err.Error(), "already exists, import it") {
+		t.Fatalf("expected existing user error, got: %v", err)
+	}
+	u, _ := srv.User(testUser.UserInfo.UID)
+	if u.DisplayName != testUser.UserInfo.DisplayName || u.PasswordHash == "" || len(u.ProviderUserInfo) != 2 {
+		t.Fatalf("existing user was overwritten: %#v", u)
+	}
+}
+
+func TestResourceFirebaseUserCustomizeDiff_releasedEmail(t *testing.T) {
+	r := resourceFirebaseUser()
+	meta := Client{ProjectID: testProjectID, userClaims: &userClaims{uids: make(map[string]string)}}
+	diff := func(uid, old, email string) error {
+		var state *terraform.InstanceState
+		if old != "" {
+			state = &terraform.InstanceState{
+				ID: uid,
+				Attributes: map[string]string{
+					"uid":                  uid,
+					"email":                old,
+					"project":              testProjectID,
+					"federated_identity.#": "0",
+				},
+			}
+		}
+		_, err := r.Diff(state, testResourceConfig(t, map[string]interface{}{
+			"uid":   uid,
+			"email": email,
+		}), meta)
+		return err
+	}
+
+	if err := diff("alice", "alice@example.com", "alice@example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Alice gives up her email to Bob
+	if err := diff("alice", "alice@example.com", "alice@example.org"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := diff("bob", "", "alice@example.com"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := diff("carol", "", "alice@example.com"); err == nil || !strings.Contains(err.Error(), `uid "bob"`) {
+		t.Fatalf("expected claim error, got: %v", err)
+	}
+}
+
+func TestResourceFirebaseUser_swappedEmails(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	providers, provider, cleanup := testUnitProviders(t, srv)
+	defer cleanup()
+
+	config := func(first, second string) string {
+		return fmt.Sprintf(`
+resource "firebase_user" "alice" {
+	uid          = "alice"
+	display_name = "Alice"
+	email        = "%s"
+	phone_number = "+14155550100"
+	photo_url    = "%s"
+}
+
+resource "firebase_user" "bob" {
+	uid          = "bob"
+	display_name = "Bob"
+	email        = "%s"
+	phone_number = "+14155550101"
+	photo_url    = "%s"
+}
+`, first, testUser.UserInfo.PhotoURL, second, testUser.UserInfo.PhotoURL)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccCheckUserDestroyWithProvider(s, provider)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("alice@example.com", "bob@example.com"),
+			},
+			{
+				// The backend only accepts the swap in two applies, but the
+				// plan is not rejected
+				Config:             config("bob@example.com", "alice@example.com"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestResourceFirebaseUser_federatedIdentityDrift(t *testing.T) {
+	srv := authtest.NewServer()
+	defer srv.Close()
+	providers, provider, cleanup := testUnitProviders(t, srv)
+	defer cleanup()
+
+	config := func(identities string) string {
+		return fmt.Sprintf(`
+resource "firebase_user" "jane" {
+	uid          = "jane"
+	display_name = "Jane Doe"
+	email        = "jane@example.com"
+	phone_number = "+14155550100"
+	photo_url    = "%s"
+
+	federated_identity {
+		provider_id   = "google.com"
+		federated_uid = "108123456789012345678"
+		email         = "jane@example.com"
+		display_name  = "Jane Doe"
+	}
+%s}
+`, testUser.UserInfo.PhotoURL, identities)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		CheckDestroy: func(s *terraform.State) error {
+			return testAccCheckUserDestroyWithProvider(s, provider)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			{
+				// Signing in updates the profile of the identity, and
+				// another provider is linked from the console
+				PreConfig: func() {
+					srv.LinkProvider("jane", &identitytoolkit.UserInfoProviderUserInfo{
+						ProviderId:  "google.com",
+						RawId:       "108123456789012345678",
+						Email:       "jane@example.com",
+						DisplayName: "Jane D.",
+						PhotoUrl:    "https://lh3.googleusercontent.com/jane.jpg",
+					})
+					srv.LinkProvider("jane", &identitytoolkit.UserInfoProviderUserInfo{
+						ProviderId: "github.com",
+						RawId:      "1234567",
+					})
+				},
+				Config:   config(""),
+				PlanOnly: true,
+			},
+			{
+				// Declaring another identity replaces the user
+				Config: config(`
+	federated_identity {
+		provider_id   = "apple.com"
+		federated_uid = "001234.abcdef"
+	}
+`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,393 @@
+package firebase
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	identitytoolkit "google.golang.org/api/identitytoolkit/v3"
+)
+
+const identityToolkitAdminEndpoint = "https://identitytoolkit.googleapis.com/admin/v2"
+
+// usersBackupRecord is a single line of a users backup. The first line of a
+// backup holds the project's password hash config, every following line
+// holds one user. Byte slices are written as standard base64, the encoding
+// auth.UserToImport expects once decoded.
+type usersBackupRecord struct {
+	HashConfig *usersBackupHashConfig `json:"hash_config,omitempty"`
+	User       *usersBackupUser       `json:"user,omitempty"`
+}
+
+type usersBackupHashConfig struct {
+	Algorithm     string `json:"algorithm"`
+	SignerKey     []byte `json:"signer_key,omitempty"`
+	SaltSeparator []byte `json:"salt_separator,omitempty"`
+	Rounds        int    `json:"rounds,omitempty"`
+	MemoryCost    int    `json:"memory_cost,omitempty"`
+}
+
+type usersBackupUser struct {
+	UID              string                `json:"uid"`
+	Email            string                `json:"email,omitempty"`
+	EmailVerified    bool                  `json:"email_verified,omitempty"`
+	DisplayName      string                `json:"display_name,omitempty"`
+	PhotoURL         string                `json:"photo_url,omitempty"`
+	PhoneNumber      string                `json:"phone_number,omitempty"`
+	Disabled         bool                  `json:"disabled,omitempty"`
+	CustomClaims     json.RawMessage       `json:"custom_claims,omitempty"`
+	PasswordHash     []byte                `json:"password_hash,omitempty"`
+	PasswordSalt     []byte                `json:"password_salt,omitempty"`
+	CreationTime     int64                 `json:"creation_time,omitempty"`
+	LastSignInTime   int64                 `json:"last_sign_in_time,omitempty"`
+	TokensValidAfter int64                 `json:"tokens_valid_after,omitempty"`
+	ProviderData     []usersBackupProvider `json:"provider_data,omitempty"`
+}
+
+type usersBackupProvider struct {
+	ProviderID  string `json:"provider_id"`
+	UID         string `json:"uid"`
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	PhotoURL    string `json:"photo_url,omitempty"`
+}
+
+// identityPlatformHashConfig is the password hash config of a project,
+// or a tenant, in the Identity Platform admin API.
+type identityPlatformHashConfig struct {
+	Algorithm     string `json:"algorithm"`
+	SignerKey     string `json:"signerKey"`
+	SaltSeparator string `json:"saltSeparator"`
+	Rounds        int    `json:"rounds"`
+	MemoryCost    int    `json:"memoryCost"`
+}
+
+type projectConfigResponse struct {
+	SignIn struct {
+		HashConfig identityPlatformHashConfig `json:"hashConfig"`
+	} `json:"signIn"`
+}
+
+type tenantConfigResponse struct {
+	HashConfig identityPlatformHashConfig `json:"hashConfig"`
+}
+
+func resourceFirebaseUsersBackup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseUsersBackupCreate,
+		Read:   resourceFirebaseUsersBackupRead,
+		Delete: resourceFirebaseUsersBackupDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project": projectSchema(),
+			"tenant_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateTenantID,
+			},
+			"filename": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"page_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntBetween(1, 1000),
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"user_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"hash_config": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"algorithm": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"signer_key": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"salt_separator": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						"rounds": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory_cost": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceFirebaseUsersBackupCreate(d *schema.ResourceData, meta interface{}) error {
+	filename := d.Get("filename").(string)
+	log.Printf("[INFO] Backing up users to %s", filename)
+
+	client, err := projectClient(d, meta)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	// Tenants hash passwords with their own config
+	tenantID := d.Get("tenant_id").(string)
+	var config identityPlatformHashConfig
+	if tenantID != "" {
+		var tenant tenantConfigResponse
+		configURL := fmt.Sprintf("%s/projects/%s/tenants/%s", identityToolkitAdminEndpoint, client.ProjectID, tenantID)
+		err = sendRequest(ctx, client.HTTP, "GET", configURL, nil, &tenant)
+		config = tenant.HashConfig
+	} else {
+		var project projectConfigResponse
+		configURL := fmt.Sprintf("%s/projects/%s/config", identityToolkitAdminEndpoint, client.ProjectID)
+		err = sendRequest(ctx, client.HTTP, "GET", configURL, nil, &project)
+		config = project.SignIn.HashConfig
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading password hash config: %s", err)
+	}
+	hashConfig, err := expandUsersBackupHashConfig(config)
+	if err != nil {
+		return fmt.Errorf("Error reading password hash config: %s", err)
+	}
+
+	// Write to a temporary file next to the target, so a failed download
+	// never replaces an earlier backup.
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	w := bufio.NewWriter(io.MultiWriter(f, h))
+	enc := json.NewEncoder(w)
+
+	if err := enc.Encode(usersBackupRecord{HashConfig: hashConfig}); err != nil {
+		return err
+	}
+
+	count := 0
+	err = downloadUsers(ctx, client, tenantID, d.Get("page_size").(int), func(page *identitytoolkit.DownloadAccountResponse) error {
+		log.Printf("[DEBUG] Writing %d users", len(page.Users))
+		for _, u := range page.Users {
+			user, err := expandUsersBackupUser(u)
+			if err != nil {
+				return fmt.Errorf("user (%s): %s", u.LocalId, err)
+			}
+			if err := enc.Encode(usersBackupRecord{User: user}); err != nil {
+				return err
+			}
+			count++
+		}
+		// Flush each page so memory use does not grow with the user count
+		return w.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("Error downloading users: %s", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Backed up %d users to %s", count, filename)
+
+	d.SetId(hex.EncodeToString(h.Sum(nil)))
+	d.Set("user_count", count)
+	d.Set("hash_config", flattenUsersBackupHashConfig(hashConfig))
+
+	return resourceFirebaseUsersBackupRead(d, meta)
+}
+
+func resourceFirebaseUsersBackupRead(d *schema.ResourceData, meta interface{}) error {
+	filename := d.Get("filename").(string)
+
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		log.Printf("[WARN] Users backup %s is gone, removing from state", filename)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != d.Id() {
+		log.Printf("[WARN] Users backup %s has changed, removing from state", filename)
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceFirebaseUsersBackupDelete(d *schema.ResourceData, meta interface{}) error {
+	// Backups are meant to outlive the configuration, so the file is kept
+	log.Printf("[INFO] Forgetting users backup: %s", d.Get("filename").(string))
+	d.SetId("")
+	return nil
+}
+
+// downloadUsers calls f with every page of the users of the client's
+// project, or of one of its tenants through the tenant's accounts API.
+func downloadUsers(ctx context.Context, client Client, tenantID string, pageSize int, f func(*identitytoolkit.DownloadAccountResponse) error) error {
+	if tenantID == "" {
+		svc, err := identitytoolkit.New(client.HTTP)
+		if err != nil {
+			return err
+		}
+		call := svc.Relyingparty.DownloadAccount(&identitytoolkit.IdentitytoolkitRelyingpartyDownloadAccountRequest{
+			MaxResults:      int64(pageSize),
+			TargetProjectId: client.ProjectID,
+		})
+		return call.Pages(ctx, f)
+	}
+
+	token := ""
+	for {
+		var page identitytoolkit.DownloadAccountResponse
+		u := fmt.Sprintf("%s/projects/%s/tenants/%s/accounts:batchGet?maxResults=%d&nextPageToken=%s",
+			identityToolkitV1Endpoint, client.ProjectID, tenantID, pageSize, url.QueryEscape(token))
+		if err := sendRequest(ctx, client.HTTP, "GET", u, nil, &page); err != nil {
+			return err
+		}
+		if err := f(&page); err != nil {
+			return err
+		}
+		if token = page.NextPageToken; token == "" {
+			return nil
+		}
+	}
+}
+
+func expandUsersBackupHashConfig(c identityPlatformHashConfig) (*usersBackupHashConfig, error) {
+	signerKey, err := base64.StdEncoding.DecodeString(c.SignerKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer key: %s", err)
+	}
+	saltSeparator, err := base64.StdEncoding.DecodeString(c.SaltSeparator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt separator: %s", err)
+	}
+	return &usersBackupHashConfig{
+		Algorithm:     c.Algorithm,
+		SignerKey:     signerKey,
+		SaltSeparator: saltSeparator,
+		Rounds:        c.Rounds,
+		MemoryCost:    c.MemoryCost,
+	}, nil
+}
+
+func flattenUsersBackupHashConfig(c *usersBackupHashConfig) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"algorithm":      c.Algorithm,
+			"signer_key":     base64.StdEncoding.EncodeToString(c.SignerKey),
+			"salt_separator": base64.StdEncoding.EncodeToString(c.SaltSeparator),
+			"rounds":         c.Rounds,
+			"memory_cost":    c.MemoryCost,
+		},
+	}
+}
+
+func expandUsersBackupUser(u *identitytoolkit.UserInfo) (*usersBackupUser, error) {
+	hash, err := decodeWebSafeBase64(u.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hash: %s", err)
+	}
+	salt, err := decodeWebSafeBase64(u.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password salt: %s", err)
+	}
+
+	user := &usersBackupUser{
+		UID:              u.LocalId,
+		Email:            u.Email,
+		EmailVerified:    u.EmailVerified,
+		DisplayName:      u.DisplayName,
+		PhotoURL:         u.PhotoUrl,
+		PhoneNumber:      u.PhoneNumber,
+		Disabled:         u.Disabled,
+		PasswordHash:     hash,
+		PasswordSalt:     salt,
+		CreationTime:     u.CreatedAt,
+		LastSignInTime:   u.LastLoginAt,
+		TokensValidAfter: u.ValidSince * 1000,
+	}
+	if u.CustomAttributes != "" {
+		user.CustomClaims = json.RawMessage(u.CustomAttributes)
+	}
+	for _, p := range u.ProviderUserInfo {
+		user.ProviderData = append(user.ProviderData, usersBackupProvider{
+			ProviderID:  p.ProviderId,
+			UID:         p.RawId,
+			Email:       p.Email,
+			DisplayName: p.DisplayName,
+			PhotoURL:    p.PhotoUrl,
+		})
+	}
+	return user, nil
+}
+
+// decodeWebSafeBase64 decodes the URL safe base64 the Identity Toolkit uses
+// for hashes and salts, which may or may not be padded.
+func decodeWebSafeBase64(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
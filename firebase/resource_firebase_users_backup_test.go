@@ -0,0 +1,171 @@
+package firebase
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceFirebaseUsersBackup(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/admin/v2/projects/%s/config", testProjectID), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"signIn":{"hashConfig":{"algorithm":"SCRYPT","signerKey":"c2lnbmVy","saltSeparator":"Bw==","rounds":8,"memoryCost":14}}}`)
+	})
+	var pages []string
+	mux.HandleFunc("/downloadAccount", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			MaxResults    int    `json:"maxResults"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pages = append(pages, req.NextPageToken)
+		if req.NextPageToken == "" {
+			fmt.Fprint(w, `{"users":[{"localId":"uid-1","email":"one@example.com","passwordHash":"aGFzaC0x","salt":"c2FsdA","customAttributes":"{\"admin\":true}","validSince":"1500000000"}],"nextPageToken":"page-2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"users":[{"localId":"uid-2","providerUserInfo":[{"providerId":"google.com","rawId":"g-2","email":"two@example.com"}]}]}`)
+	})
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		identityToolkitEndpoint:      srv.URL,
+		identityToolkitAdminEndpoint: srv.URL + "/admin/v2",
+	})
+
+	dir, err := ioutil.TempDir("", "terraform-provider-firebase")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "users.jsonl")
+
+	r := resourceFirebaseUsersBackup()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"filename":  filename,
+		"page_size": 1,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(pages) != "[ page-2]" {
+		t.Fatalf("unexpected pages: %q", pages)
+	}
+	if d.Id() == "" || d.Get("user_count").(int) != 2 {
+		t.Fatalf("unexpected state: id %q, user_count %d", d.Id(), d.Get("user_count"))
+	}
+	if d.Get("hash_config.0.signer_key") != "c2lnbmVy" || d.Get("hash_config.0.rounds") != 8 {
+		t.Fatalf("incorrect hash_config: %#v", d.Get("hash_config"))
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+	var records []usersBackupRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record usersBackupRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 || records[0].HashConfig == nil {
+		t.Fatalf("expected hash config and two users, got %#v", records)
+	}
+	if string(records[0].HashConfig.SaltSeparator) != "\x07" {
+		t.Fatalf("incorrect salt separator: %q", records[0].HashConfig.SaltSeparator)
+	}
+	one := records[1].User
+	if string(one.PasswordHash) != "hash-1" || string(one.PasswordSalt) != "salt" {
+		t.Fatalf("incorrect password hash or salt: %q %q", one.PasswordHash, one.PasswordSalt)
+	}
+	if string(one.CustomClaims) != `{"admin":true}` || one.TokensValidAfter != 1500000000000 {
+		t.Fatalf("incorrect user: %#v", one)
+	}
+	if p := records[2].User.ProviderData; len(p) != 1 || p[0].ProviderID != "google.com" || p[0].UID != "g-2" {
+		t.Fatalf("incorrect provider data: %#v", p)
+	}
+
+	// Hashes are stored as standard base64 so they decode into UserToImport
+	line, _ := json.Marshal(records[1])
+	var raw map[string]map[string]interface{}
+	json.Unmarshal(line, &raw)
+	if raw["user"]["password_hash"] != base64.StdEncoding.EncodeToString([]byte("hash-1")) {
+		t.Fatalf("incorrect encoding: %#v", raw["user"]["password_hash"])
+	}
+
+	if err := ioutil.WriteFile(filename, []byte("tampered\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := r.Read(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatalf("modified backup was kept in state")
+	}
+}
+
+func TestResourceFirebaseUsersBackup_tenant(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/admin/v2/projects/%s/tenants/acme-0", testProjectID), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"projects/mock-project-id/tenants/acme-0","hashConfig":{"algorithm":"SCRYPT","signerKey":"dGVuYW50","saltSeparator":"Bw==","rounds":8,"memoryCost":14}}`)
+	})
+	var pages []string
+	mux.HandleFunc(fmt.Sprintf("/v1/projects/%s/tenants/acme-0/accounts:batchGet", testProjectID), func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("nextPageToken")
+		pages = append(pages, token)
+		if token == "" {
+			fmt.Fprint(w, `{"users":[{"localId":"uid-1","email":"one@example.com"}],"nextPageToken":"page-2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"users":[{"localId":"uid-2","email":"two@example.com"}]}`)
+	})
+	mux.HandleFunc("/downloadAccount", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected project scoped request: %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+	srv := testServer(mux)
+	defer srv.Close()
+
+	meta := testClient(t, srv, map[string]string{
+		identityToolkitEndpoint:      srv.URL,
+		identityToolkitV1Endpoint:    srv.URL + "/v1",
+		identityToolkitAdminEndpoint: srv.URL + "/admin/v2",
+	})
+
+	dir, err := ioutil.TempDir("", "terraform-provider-firebase")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := resourceFirebaseUsersBackup()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tenant_id": "acme-0",
+		"filename":  filepath.Join(dir, "users.jsonl"),
+		"page_size": 1,
+	})
+	if err := r.Create(d, meta); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if fmt.Sprint(pages) != "[ page-2]" || d.Get("user_count").(int) != 2 {
+		t.Fatalf("incorrect download: pages %q, user_count %d", pages, d.Get("user_count"))
+	}
+	if d.Get("hash_config.0.signer_key") != "dGVuYW50" {
+		t.Fatalf("tenant hash config not used: %#v", d.Get("hash_config"))
+	}
+}
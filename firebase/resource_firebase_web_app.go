@@ -0,0 +1,82 @@
+package firebase
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type webApp struct {
+	DisplayName string   `json:"displayName"`
+	AppURLs     []string `json:"appUrls,omitempty"`
+}
+
+// webAppFields maps the schema to the web app fields for update masks.
+var webAppFields = map[string]string{
+	"display_name": "displayName",
+}
+
+func resourceFirebaseWebApp() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceFirebaseWebAppCreate,
+		Read:   resourceFirebaseWebAppRead,
+		Update: resourceFirebaseWebAppUpdate,
+		Delete: resourceFirebaseWebAppDelete,
+		Importer: &schema.ResourceImporter{
+			State: firebaseWebAppType.importState,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: firebaseAppSchema(map[string]*schema.Schema{
+			"display_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"app_urls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		}),
+	}
+}
+
+func resourceFirebaseWebAppCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseWebAppType.create(d, meta, expandWebApp(d)); err != nil {
+		return err
+	}
+	return resourceFirebaseWebAppRead(d, meta)
+}
+
+func resourceFirebaseWebAppRead(d *schema.ResourceData, meta interface{}) error {
+	var app webApp
+	if ok, err := firebaseWebAppType.read(d, meta, &app); !ok {
+		return err
+	}
+
+	d.Set("display_name", app.DisplayName)
+	d.Set("app_urls", app.AppURLs)
+
+	return nil
+}
+
+func resourceFirebaseWebAppUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := firebaseWebAppType.update(d, meta, expandWebApp(d), webAppFields); err != nil {
+		return err
+	}
+	return resourceFirebaseWebAppRead(d, meta)
+}
+
+func resourceFirebaseWebAppDelete(d *schema.ResourceData, meta interface{}) error {
+	return firebaseWebAppType.remove(d, meta)
+}
+
+func expandWebApp(d *schema.ResourceData) *webApp {
+	return &webApp{
+		DisplayName: d.Get("display_name").(string),
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"net/mail"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/structure"
 )
 
 func validateEmail(v interface{}, k string) (ws []string, errors []error) {
@@ -18,6 +23,29 @@ func validateEmail(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
+// normalizeEmail lowercases an email the way Firebase stores it.
+func normalizeEmail(v interface{}) string {
+	return strings.ToLower(strings.TrimSpace(v.(string)))
+}
+
+// phoneNumberSeparators are the characters commonly used to group the digits
+// of a phone number, which Firebase drops.
+var phoneNumberSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// normalizePhoneNumber returns the compact E.164 form of a phone number
+// written with separators, e.g. +14155552671 for +1 (415) 555-2671.
+func normalizePhoneNumber(v interface{}) string {
+	return phoneNumberSeparators.Replace(strings.TrimSpace(v.(string)))
+}
+
+// suppressNormalizedDiff returns a DiffSuppressFunc ignoring differences
+// that normalize away, e.g. in states written before normalization.
+func suppressNormalizedDiff(normalize schema.SchemaStateFunc) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return normalize(old) == normalize(new)
+	}
+}
+
 func validateURL(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 
@@ -33,10 +61,167 @@ func validateE164PhoneNumber(v interface{}, k string) (ws []string, errors []err
 	value := v.(string)
 
 	// https://en.wikipedia.org/wiki/E.164
-	if !regexp.MustCompile(`^\+(?:[0-9]●?){6,14}[0-9]$`).MatchString(value) {
+	if !regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`).MatchString(normalizePhoneNumber(value)) {
 		errors = append(errors, fmt.Errorf(
 			"%q should be a E.164: %q",
 			k, value))
 	}
 	return
 }
+
+func validateFederatedProviderID(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "" || value == "password" || value == "phone" || value == "firebase" {
+		errors = append(errors, fmt.Errorf(
+			"%q should be a federated identity provider such as google.com: %q",
+			k, value))
+	}
+	return
+}
+
+// reservedClaims mirrors the claim names that auth.Client.CustomToken refuses
+// to encode as developer claims.
+var reservedClaims = []string{
+	"acr", "amr", "at_hash", "aud", "auth_time", "azp", "cnf", "c_hash",
+	"exp", "firebase", "iat", "iss", "jti", "nbf", "nonce", "sub",
+}
+
+func validateDeveloperClaims(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value == "" {
+		return
+	}
+
+	claims, err := structure.ExpandJsonFromString(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q contains an invalid JSON object: %s",
+			k, err))
+		return
+	}
+
+	for _, r := range reservedClaims {
+		if _, ok := claims[r]; ok {
+			errors = append(errors, fmt.Errorf(
+				"%q contains the reserved claim %q",
+				k, r))
+		}
+	}
+	return
+}
+
+func validateTopicName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^(/topics/)?(private/)?[a-zA-Z0-9-_.~%]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be a topic name matching /topics/[a-zA-Z0-9-_.~%%]+: %q",
+			k, value))
+	}
+	return
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if d, err := time.ParseDuration(value); err != nil || d < 0 {
+		errors = append(errors, fmt.Errorf(
+			"%q should be a non-negative duration such as 3600s: %q",
+			k, value))
+	}
+	return
+}
+
+func validateTenantID(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be a tenant ID of letters, digits and hyphens: %q",
+			k, value))
+	}
+	return
+}
+
+func validateTenantDisplayName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{3,19}$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be 4 to 20 letters, digits and hyphens starting with a letter: %q",
+			k, value))
+	}
+	return
+}
+
+func validateAndroidPackageName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*(\.[a-zA-Z][a-zA-Z0-9_]*)+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be an Android package name such as com.example.app: %q",
+			k, value))
+	}
+	return
+}
+
+// validateCertificateHash returns a validator for hex fingerprints of size
+// bytes, optionally colon separated as printed by keytool.
+func validateCertificateHash(size int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(string)
+
+		hash := normalizeCertificateHash(value)
+		if len(hash) != 2*size || !regexp.MustCompile(`^[0-9a-f]+$`).MatchString(hash) {
+			errors = append(errors, fmt.Errorf(
+				"%q should be a %d byte hex fingerprint: %q",
+				k, size, value))
+		}
+		return
+	}
+}
+
+func validateAppStoreID(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[0-9]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be a numeric App Store ID: %q",
+			k, value))
+	}
+	return
+}
+
+func validateHostingSiteID(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[a-z0-9][a-z0-9-]{4,61}[a-z0-9]$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be 6 to 63 lowercase letters, digits and hyphens: %q",
+			k, value))
+	}
+	return
+}
+
+func validateHostingChannelID(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be up to 63 lowercase letters, digits, hyphens and underscores: %q",
+			k, value))
+	}
+	return
+}
+
+func validateRulesReleaseName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !regexp.MustCompile(`^(cloud\.firestore|firebase\.storage/[a-z0-9][a-z0-9._-]*)$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q should be cloud.firestore or firebase.storage/<bucket>: %q",
+			k, value))
+	}
+	return
+}
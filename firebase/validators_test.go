@@ -0,0 +1,144 @@
+package firebase
+
+import (
+	"testing"
+)
+
+func TestValidateDeveloperClaims(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "", ErrCount: 0},
+		{Value: `{"admin":true}`, ErrCount: 0},
+		{Value: `{"admin":`, ErrCount: 1},
+		{Value: `{"sub":"other"}`, ErrCount: 1},
+		{Value: `{"iss":"other","aud":"other","admin":true}`, ErrCount: 2},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateDeveloperClaims(tc.Value, "claims")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}
+
+func TestValidateFederatedProviderID(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "google.com", ErrCount: 0},
+		{Value: "saml.example", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "password", ErrCount: 1},
+		{Value: "phone", ErrCount: 1},
+		{Value: "firebase", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateFederatedProviderID(tc.Value, "provider_id")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}
+
+func TestValidateTopicName(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "kiosks", ErrCount: 0},
+		{Value: "/topics/kiosks", ErrCount: 0},
+		{Value: "/topics/private/kiosks-1.2_3~%", ErrCount: 0},
+		{Value: "", ErrCount: 1},
+		{Value: "/topics/", ErrCount: 1},
+		{Value: "/topic/kiosks", ErrCount: 1},
+		{Value: "kiosks and tablets", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateTopicName(tc.Value, "topic")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}
+
+func TestValidateTenantDisplayName(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "acme", ErrCount: 0},
+		{Value: "acme-eu-west-1", ErrCount: 0},
+		{Value: "abc", ErrCount: 1},
+		{Value: "1acme", ErrCount: 1},
+		{Value: "acme_corp", ErrCount: 1},
+		{Value: "acme-corporation-europe", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateTenantDisplayName(tc.Value, "display_name")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}
+
+func TestValidateE164PhoneNumber(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "+14155552671", ErrCount: 0},
+		{Value: "+1 (415) 555-2671", ErrCount: 0},
+		{Value: "+44 20.7946.0958", ErrCount: 0},
+		{Value: "+1234567", ErrCount: 0},
+		{Value: "14155552671", ErrCount: 1},
+		{Value: "+04155552671", ErrCount: 1},
+		{Value: "+123456", ErrCount: 1},
+		{Value: "+1234567890123456", ErrCount: 1},
+		{Value: "+1415555267x", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateE164PhoneNumber(tc.Value, "phone_number")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}
+
+func TestNormalizeIdentifiers(t *testing.T) {
+	if v := normalizeEmail(" John.Doe@Example.COM"); v != "john.doe@example.com" {
+		t.Fatalf("incorrect email: %q", v)
+	}
+	if v := normalizePhoneNumber("+1 (415) 555-2671"); v != "+14155552671" {
+		t.Fatalf("incorrect phone number: %q", v)
+	}
+	if !suppressNormalizedDiff(normalizeEmail)("email", "John.Doe@example.com", "john.doe@example.com", nil) {
+		t.Fatalf("expected email case difference to be suppressed")
+	}
+}
+
+func TestValidateCertificateHash(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{Value: "da39a3ee5e6b4b0d3255bfef95601890afd80709", ErrCount: 0},
+		{Value: "DA:39:A3:EE:5E:6B:4B:0D:32:55:BF:EF:95:60:18:90:AF:D8:07:09", ErrCount: 0},
+		{Value: "da39a3ee5e6b4b0d3255bfef95601890afd807", ErrCount: 1},
+		{Value: "zz39a3ee5e6b4b0d3255bfef95601890afd80709", ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateCertificateHash(20)(tc.Value, "sha1_hashes")
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("expected %d errors for %q, got %d: %v", tc.ErrCount, tc.Value, len(errors), errors)
+		}
+	}
+}